@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/alecthomas/kingpin"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/evm-saver-svc/internal/services/evm"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// backfillCmd reprocesses deposits of a closed block range without touching listeners checkpoints.
+type backfillCmd struct {
+	cmd     *kingpin.CmdClause
+	network *string
+	from    *uint64
+	to      *uint64
+}
+
+func newBackfillCmd(app *kingpin.Application) *backfillCmd {
+	cmd := app.Command("backfill", "submit deposits of the block range missing on core and exit")
+
+	return &backfillCmd{
+		cmd:     cmd,
+		network: cmd.Flag("network", "network to backfill, required if several networks are configured").String(),
+		from:    cmd.Flag("from", "first block of the range").Required().Uint64(),
+		to:      cmd.Flag("to", "last block of the range (inclusive)").Required().Uint64(),
+	}
+}
+
+func (b *backfillCmd) Matches(cmd string) bool {
+	return cmd == b.cmd.FullCommand()
+}
+
+func (b *backfillCmd) Run(cfg config.Config) error {
+	network, err := selectNetwork(cfg, *b.network)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	report, err := evm.Backfill(ctx, cfg, network, *b.from, *b.to)
+	cfg.Log().WithFields(report.Fields()).Info("backfill finished")
+
+	if err != nil {
+		return errors.Wrap(err, "failed to backfill")
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/alecthomas/kingpin"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// controlCmd pauses, resumes and rewinds listeners of the running service through its grpc api.
+type controlCmd struct {
+	addr     *string
+	network  *string
+	listener *string
+
+	list            *kingpin.CmdClause
+	pause           *kingpin.CmdClause
+	resume          *kingpin.CmdClause
+	pauseBroadcast  *kingpin.CmdClause
+	resumeBroadcast *kingpin.CmdClause
+
+	rewind      *kingpin.CmdClause
+	rewindBlock *uint64
+}
+
+func newControlCmd(app *kingpin.Application) *controlCmd {
+	cmd := app.Command("control", "pause, resume and rewind listeners of the running service")
+
+	c := &controlCmd{
+		addr:            cmd.Flag("addr", "grpc api address of the running service").Default("localhost:8000").String(),
+		network:         cmd.Flag("network", "network of the listener, required if several networks are configured").String(),
+		listener:        cmd.Flag("listener", "listener name, deposits_scanner by default").String(),
+		list:            cmd.Command("list", "show listeners state"),
+		pause:           cmd.Command("pause", "stop the listener from scanning"),
+		resume:          cmd.Command("resume", "let the paused listener scan again"),
+		pauseBroadcast:  cmd.Command("pause-broadcast", "keep scanning, but buffer deposits instead of submitting them"),
+		resumeBroadcast: cmd.Command("resume-broadcast", "submit buffered deposits and get back to submitting new ones"),
+		rewind:          cmd.Command("rewind", "make the listener continue from the block"),
+	}
+
+	c.rewindBlock = c.rewind.Arg("block", "block to continue from").Required().Uint64()
+
+	return c
+}
+
+func (c *controlCmd) Matches(cmd string) bool {
+	for _, clause := range []*kingpin.CmdClause{c.list, c.pause, c.resume, c.pauseBroadcast, c.resumeBroadcast, c.rewind} {
+		if cmd == clause.FullCommand() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *controlCmd) Run(cmd string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, con, err := dialAPI(*c.addr)
+	if err != nil {
+		return err
+	}
+	defer con.Close()
+
+	req := &api.ListenerRequest{
+		Network:  *c.network,
+		Listener: *c.listener,
+	}
+
+	var resp proto.Message
+
+	switch cmd {
+	case c.list.FullCommand():
+		resp, err = client.ListControls(ctx, &api.ListControlsRequest{Network: *c.network})
+	case c.pause.FullCommand():
+		resp, err = client.PauseListener(ctx, req)
+	case c.resume.FullCommand():
+		resp, err = client.ResumeListener(ctx, req)
+	case c.pauseBroadcast.FullCommand():
+		resp, err = client.PauseBroadcast(ctx, req)
+	case c.resumeBroadcast.FullCommand():
+		resp, err = client.ResumeBroadcast(ctx, req)
+	case c.rewind.FullCommand():
+		resp, err = client.RewindListener(ctx, &api.RewindListenerRequest{
+			Network:  *c.network,
+			Listener: *c.listener,
+			Block:    *c.rewindBlock,
+		})
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+
+	return printProto(resp)
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/alecthomas/kingpin"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// listenersCmd shows progress of listeners through the grpc api of the running service.
+type listenersCmd struct {
+	cmd     *kingpin.CmdClause
+	addr    *string
+	network *string
+}
+
+func newListenersCmd(app *kingpin.Application) *listenersCmd {
+	cmd := app.Command("listeners", "show progress and lag of listeners")
+
+	return &listenersCmd{
+		cmd:     cmd,
+		addr:    cmd.Flag("addr", "grpc api address of the running service").Default("localhost:8000").String(),
+		network: cmd.Flag("network", "show listeners of the given network only").String(),
+	}
+}
+
+func (l *listenersCmd) Matches(cmd string) bool {
+	return cmd == l.cmd.FullCommand()
+}
+
+func (l *listenersCmd) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, con, err := dialAPI(*l.addr)
+	if err != nil {
+		return err
+	}
+	defer con.Close()
+
+	resp, err := client.ListListeners(ctx, &api.ListListenersRequest{Network: *l.network})
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+
+	return printProto(resp)
+}
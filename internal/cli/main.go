@@ -29,7 +29,10 @@ func Run(args []string) bool {
 		}
 	}()
 
-	cfg := config.New(kv.MustFromEnv())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.New(ctx, kv.MustFromEnv())
 	log = cfg.Log()
 
 	app := kingpin.New("evm-saver-svc", "")
@@ -37,9 +40,27 @@ func Run(args []string) bool {
 	runCmd := app.Command("run", "run command")
 
 	allCmd := runCmd.Command("all", "run all services (evm listeners, evm voter, grpc api)")
-	apiCmd := runCmd.Command("api", "run grpc api")
+	apiCmd := runCmd.Command("api", "run grpc api, the saver api is forwarded to the saver")
 	voterCmd := runCmd.Command("voter", "run voter")
-	saver := runCmd.Command("saver", "run saver")
+	saver := runCmd.Command("saver", "run saver and its grpc api")
+
+	quarantine := newQuarantineCmd(app)
+	backfill := newBackfillCmd(app)
+	rejected := newRejectedCmd(app)
+	listeners := newListenersCmd(app)
+	submitTx := newSubmitTxCmd(app)
+	receiptProof := newReceiptProofCmd(app)
+	control := newControlCmd(app)
+
+	forceStart := false
+	startBlock := runCmd.Flag("start-block", "start listeners from the given block ignoring saved checkpoints").
+		Action(func(*kingpin.ParseContext) error {
+			forceStart = true
+			return nil
+		}).
+		Uint64()
+	startNetwork := runCmd.Flag("network", "network the start block is given for, required if several networks are configured").
+		String()
 
 	cmd, err := app.Parse(args[1:])
 	if err != nil {
@@ -47,10 +68,81 @@ func Run(args []string) bool {
 		return false
 	}
 
-	var wg sync.WaitGroup
+	if quarantine.Matches(cmd) {
+		if err := quarantine.Run(cmd); err != nil {
+			log.WithError(err).Error("quarantine command failed")
+			return false
+		}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+		return true
+	}
+
+	if rejected.Matches(cmd) {
+		if err := rejected.Run(); err != nil {
+			log.WithError(err).Error("rejected command failed")
+			return false
+		}
+
+		return true
+	}
+
+	if listeners.Matches(cmd) {
+		if err := listeners.Run(); err != nil {
+			log.WithError(err).Error("listeners command failed")
+			return false
+		}
+
+		return true
+	}
+
+	if control.Matches(cmd) {
+		if err := control.Run(cmd); err != nil {
+			log.WithError(err).Error("control command failed")
+			return false
+		}
+
+		return true
+	}
+
+	if submitTx.Matches(cmd) {
+		if err := submitTx.Run(); err != nil {
+			log.WithError(err).Error("submit-tx command failed")
+			return false
+		}
+
+		return true
+	}
+
+	if receiptProof.Matches(cmd) {
+		if err := receiptProof.Run(); err != nil {
+			log.WithError(err).Error("receipt-proof command failed")
+			return false
+		}
+
+		return true
+	}
+
+	if backfill.Matches(cmd) {
+		if err := backfill.Run(cfg); err != nil {
+			log.WithError(err).Error("backfill command failed")
+			return false
+		}
+
+		return true
+	}
+
+	if forceStart {
+		network, err := selectNetwork(cfg, *startNetwork)
+		if err != nil {
+			log.WithError(err).Error("failed to apply start block")
+			return false
+		}
+
+		network.StartFromBlock = *startBlock
+		network.ForceStartFromBlock = true
+	}
+
+	var wg sync.WaitGroup
 
 	run := func(f func(ctx context.Context, cfg config.Config), name string) {
 		wg.Add(1)
@@ -66,15 +158,40 @@ func Run(args []string) bool {
 	}
 
 	runSaver := func() {
-		cfg.Log().Info("starting all savers")
-		run(evm.RunIERC20Listener, "erc20-listener")
-		run(evm.RunNativeListener, "native-listener")
+		for _, network := range cfg.Networks() {
+			network := network
+			enabled := network.Listeners
+
+			cfg.Log().WithFields(logan.F{
+				"network": network.NetworkName,
+				"native":  enabled.Native,
+				"erc20":   enabled.ERC20,
+				"erc721":  enabled.ERC721,
+				"erc1155": enabled.ERC1155,
+			}).Info("starting savers")
+
+			run(func(ctx context.Context, cfg config.Config) {
+				evm.RunDepositsScanner(ctx, cfg, network)
+			}, "deposits-scanner-"+network.NetworkName)
+		}
+	}
+
+	// the voter keeps receipt proofs only in the process owning the storage
+	runVoter := func(withStorage bool) {
+		run(func(ctx context.Context, cfg config.Config) {
+			opts := voting.RouterOpts{TxStore: cfg.TxStore()}
+			if withStorage {
+				opts.Proofs = cfg.Storage().Proofs()
+			}
+
+			voting.RunVoter(ctx, cfg, opts)
+		}, "voter")
 	}
 
 	runAll := func() {
 		cfg.Log().Info("starting all services")
 
-		run(voting.RunVoter, "voter")
+		runVoter(true)
 		run(grpc.RunAPI, "grpc-api")
 		runSaver()
 	}
@@ -87,11 +204,12 @@ func Run(args []string) bool {
 	case allCmd.FullCommand():
 		runAll()
 	case apiCmd.FullCommand():
-		run(grpc.RunAPI, "grpc-api")
+		run(grpc.RunStandaloneAPI, "grpc-api")
 	case saver.FullCommand():
+		run(grpc.RunSaverAPI, "saver-grpc-api")
 		runSaver()
 	case voterCmd.FullCommand():
-		run(voting.RunVoter, "voter")
+		runVoter(false)
 	default:
 		panic(errors.From(errors.New("unknown command"), logan.F{
 			"raw_command": cmd,
@@ -118,3 +236,24 @@ func Run(args []string) bool {
 
 	return true
 }
+
+// selectNetwork returns the network by name. The name may be omitted if only one network is configured.
+func selectNetwork(cfg config.Config, name string) (*config.Ethereum, error) {
+	if name == "" {
+		networks := cfg.Networks()
+		if len(networks) != 1 {
+			return nil, errors.New("network must be specified, several networks are configured")
+		}
+
+		return networks[0], nil
+	}
+
+	network := cfg.Network(name)
+	if network == nil {
+		return nil, errors.From(errors.New("network is not configured"), logan.F{
+			"network": name,
+		})
+	}
+
+	return network, nil
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/alecthomas/kingpin"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// receiptProofCmd prints the receipt proof a vote on deposits of the transaction is based on.
+type receiptProofCmd struct {
+	cmd         *kingpin.CmdClause
+	addr        *string
+	network     *string
+	txHash      *string
+	logIndex    *uint64
+	logIndexSet bool
+}
+
+func newReceiptProofCmd(app *kingpin.Application) *receiptProofCmd {
+	cmd := app.Command("receipt-proof", "print the proof of the transaction receipt against the block header")
+
+	r := &receiptProofCmd{
+		cmd:     cmd,
+		addr:    cmd.Flag("addr", "grpc api address of the running service").Default("localhost:8000").String(),
+		network: cmd.Flag("network", "network of the transaction, required if several networks are configured").String(),
+		txHash:  cmd.Arg("hash", "deposit transaction hash").Required().String(),
+	}
+
+	r.logIndex = cmd.Flag("log-index", "print the proven log with the index as well").
+		Action(func(*kingpin.ParseContext) error {
+			r.logIndexSet = true
+			return nil
+		}).
+		Uint64()
+
+	return r
+}
+
+func (r *receiptProofCmd) Matches(cmd string) bool {
+	return cmd == r.cmd.FullCommand()
+}
+
+func (r *receiptProofCmd) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, con, err := dialAPI(*r.addr)
+	if err != nil {
+		return err
+	}
+	defer con.Close()
+
+	req := &api.GetReceiptProofRequest{
+		Network: *r.network,
+		TxHash:  *r.txHash,
+	}
+
+	if r.logIndexSet {
+		req.LogIndex = r.logIndex
+	}
+
+	resp, err := client.GetReceiptProof(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+
+	return printProto(resp)
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alecthomas/kingpin"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// quarantineCmd manages poison deposit events through the grpc api of the running service, as the
+// storage is held by the service itself.
+type quarantineCmd struct {
+	addr *string
+
+	list        *kingpin.CmdClause
+	listNetwork *string
+
+	retry         *kingpin.CmdClause
+	retryNetwork  *string
+	retryTxHash   *string
+	retryLogIndex *uint64
+
+	discard         *kingpin.CmdClause
+	discardNetwork  *string
+	discardTxHash   *string
+	discardLogIndex *uint64
+}
+
+func newQuarantineCmd(app *kingpin.Application) *quarantineCmd {
+	cmd := app.Command("quarantine", "manage deposit events set aside by listeners")
+
+	q := &quarantineCmd{
+		addr:    cmd.Flag("addr", "grpc api address of the running service").Default("localhost:8000").String(),
+		list:    cmd.Command("list", "list quarantined events"),
+		retry:   cmd.Command("retry", "rebuild and broadcast the transfer message of a quarantined event"),
+		discard: cmd.Command("discard", "drop a quarantined event"),
+	}
+
+	q.listNetwork = q.list.Flag("network", "list events of the given network only").String()
+
+	q.retryNetwork = q.retry.Flag("network", "network of the event").String()
+	q.retryTxHash = q.retry.Arg("tx-hash", "deposit transaction hash").Required().String()
+	q.retryLogIndex = q.retry.Arg("log-index", "deposit log index").Required().Uint64()
+
+	q.discardNetwork = q.discard.Flag("network", "network of the event").String()
+	q.discardTxHash = q.discard.Arg("tx-hash", "deposit transaction hash").Required().String()
+	q.discardLogIndex = q.discard.Arg("log-index", "deposit log index").Required().Uint64()
+
+	return q
+}
+
+func (q *quarantineCmd) Matches(cmd string) bool {
+	return cmd == q.list.FullCommand() || cmd == q.retry.FullCommand() || cmd == q.discard.FullCommand()
+}
+
+func (q *quarantineCmd) Run(cmd string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, con, err := dialAPI(*q.addr)
+	if err != nil {
+		return err
+	}
+	defer con.Close()
+
+	var resp proto.Message
+
+	switch cmd {
+	case q.list.FullCommand():
+		resp, err = client.ListQuarantined(ctx, &api.ListQuarantinedRequest{
+			Network: *q.listNetwork,
+		})
+	case q.retry.FullCommand():
+		resp, err = client.RetryQuarantined(ctx, &api.QuarantinedEventRequest{
+			Network:  *q.retryNetwork,
+			TxHash:   *q.retryTxHash,
+			LogIndex: *q.retryLogIndex,
+		})
+	case q.discard.FullCommand():
+		resp, err = client.DiscardQuarantined(ctx, &api.QuarantinedEventRequest{
+			Network:  *q.discardNetwork,
+			TxHash:   *q.discardTxHash,
+			LogIndex: *q.discardLogIndex,
+		})
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+
+	return printProto(resp)
+}
+
+func dialAPI(addr string) (api.EvmSaverClient, *grpc.ClientConn, error) {
+	con, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to dial grpc api")
+	}
+
+	return api.NewEvmSaverClient(con), con, nil
+}
+
+func printProto(msg proto.Message) error {
+	raw, err := protojson.MarshalOptions{Multiline: true, UseProtoNames: true, EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal response")
+	}
+
+	fmt.Println(string(raw))
+	return nil
+}
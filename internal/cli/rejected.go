@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/alecthomas/kingpin"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// rejectedCmd lists deposit events rejected by the deposit policy through the grpc api of the running
+// service.
+type rejectedCmd struct {
+	cmd     *kingpin.CmdClause
+	addr    *string
+	network *string
+}
+
+func newRejectedCmd(app *kingpin.Application) *rejectedCmd {
+	cmd := app.Command("rejected", "list deposit events rejected by the deposit policy")
+
+	return &rejectedCmd{
+		cmd:     cmd,
+		addr:    cmd.Flag("addr", "grpc api address of the running service").Default("localhost:8000").String(),
+		network: cmd.Flag("network", "list events of the given network only").String(),
+	}
+}
+
+func (r *rejectedCmd) Matches(cmd string) bool {
+	return cmd == r.cmd.FullCommand()
+}
+
+func (r *rejectedCmd) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, con, err := dialAPI(*r.addr)
+	if err != nil {
+		return err
+	}
+	defer con.Close()
+
+	resp, err := client.ListRejected(ctx, &api.ListRejectedRequest{Network: *r.network})
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+
+	return printProto(resp)
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/alecthomas/kingpin"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// submitTxCmd pushes deposits of a transaction to core through the grpc api of the running service
+// without moving listeners checkpoints.
+type submitTxCmd struct {
+	cmd         *kingpin.CmdClause
+	addr        *string
+	network     *string
+	txHash      *string
+	logIndex    *uint64
+	logIndexSet bool
+}
+
+func newSubmitTxCmd(app *kingpin.Application) *submitTxCmd {
+	cmd := app.Command("submit-tx", "submit deposits of the transaction to core")
+
+	s := &submitTxCmd{
+		cmd:     cmd,
+		addr:    cmd.Flag("addr", "grpc api address of the running service").Default("localhost:8000").String(),
+		network: cmd.Flag("network", "network of the transaction, required if several networks are configured").String(),
+		txHash:  cmd.Arg("hash", "deposit transaction hash").Required().String(),
+	}
+
+	s.logIndex = cmd.Flag("log-index", "submit only the deposit with the log index instead of all deposits of the transaction").
+		Action(func(*kingpin.ParseContext) error {
+			s.logIndexSet = true
+			return nil
+		}).
+		Uint64()
+
+	return s
+}
+
+func (s *submitTxCmd) Matches(cmd string) bool {
+	return cmd == s.cmd.FullCommand()
+}
+
+func (s *submitTxCmd) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, con, err := dialAPI(*s.addr)
+	if err != nil {
+		return err
+	}
+	defer con.Close()
+
+	req := &api.SubmitDepositRequest{
+		Network: *s.network,
+		TxHash:  *s.txHash,
+	}
+
+	if s.logIndexSet {
+		req.LogIndex = s.logIndex
+	}
+
+	resp, err := client.SubmitDeposit(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+
+	return printProto(resp)
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	"github.com/spf13/cast"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Contract is a bridge contract deployment active in the block range.
+type Contract struct {
+	Address   common.Address `fig:"address,required"`
+	FromBlock uint64         `fig:"from_block"`
+	// ToBlock is the last block (inclusive) the contract is active in, zero if it is still active
+	ToBlock uint64 `fig:"to_block"`
+	// Version is the event set the contract emits
+	Version string `fig:"version"`
+}
+
+func (c Contract) isActive(block uint64) bool {
+	return block >= c.FromBlock && (c.ToBlock == 0 || block <= c.ToBlock)
+}
+
+// Contracts are bridge contracts of the network ordered by their active ranges, which do not overlap.
+type Contracts []Contract
+
+// At returns the contract active in the block, nil if there is none.
+func (c Contracts) At(block uint64) *Contract {
+	for i := range c {
+		if c[i].isActive(block) {
+			return &c[i]
+		}
+	}
+
+	return nil
+}
+
+// Accepts reports whether the log is emitted by the contract active in its block and belongs to the
+// event set of the contract.
+func (c Contracts) Accepts(log types.Log) bool {
+	contract := c.At(log.BlockNumber)
+	if contract == nil || contract.Address != log.Address || len(log.Topics) == 0 {
+		return false
+	}
+
+	return events.HasTopic(contract.Version, log.Topics[0])
+}
+
+// Addresses returns addresses of all contracts without duplicates.
+func (c Contracts) Addresses() []common.Address {
+	seen := make(map[common.Address]struct{}, len(c))
+	result := make([]common.Address, 0, len(c))
+
+	for _, contract := range c {
+		if _, ok := seen[contract.Address]; ok {
+			continue
+		}
+
+		seen[contract.Address] = struct{}{}
+		result = append(result, contract.Address)
+	}
+
+	return result
+}
+
+// resolveContracts turns the single contract_addr into the contracts list active from the genesis, or
+// validates the configured list. ContractAddr is set to the latest contract.
+func (e *Ethereum) resolveContracts() error {
+	if len(e.Contracts) == 0 {
+		if e.ContractAddr == (common.Address{}) {
+			return errors.New("either contract_addr or contracts must be set")
+		}
+
+		e.Contracts = Contracts{{Address: e.ContractAddr, Version: events.Version1}}
+		return nil
+	}
+
+	if e.ContractAddr != (common.Address{}) {
+		return errors.New("only one of contract_addr and contracts can be set")
+	}
+
+	sort.SliceStable(e.Contracts, func(i, j int) bool {
+		return e.Contracts[i].FromBlock < e.Contracts[j].FromBlock
+	})
+
+	for i := range e.Contracts {
+		contract := &e.Contracts[i]
+
+		if contract.Version == "" {
+			contract.Version = events.Version1
+		}
+
+		fields := logan.F{
+			"address": contract.Address,
+			"version": contract.Version,
+		}
+
+		if !events.IsKnownVersion(contract.Version) {
+			return errors.From(errors.New("unknown contract version"), fields)
+		}
+
+		if contract.ToBlock != 0 && contract.ToBlock < contract.FromBlock {
+			return errors.From(errors.New("contract active range ends before it starts"), fields)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		if prev := e.Contracts[i-1]; prev.ToBlock == 0 || prev.ToBlock >= contract.FromBlock {
+			return errors.From(errors.New("contract active ranges overlap"), logan.F{
+				"address":          contract.Address,
+				"previous_address": prev.Address,
+			})
+		}
+	}
+
+	e.ContractAddr = e.Contracts[len(e.Contracts)-1].Address
+	return nil
+}
+
+var contractHooks = figure.Hooks{
+	"common.Address": addressHook,
+}
+
+func contractsHook(raw interface{}) (reflect.Value, error) {
+	if raw == nil {
+		return reflect.ValueOf(Contracts(nil)), nil
+	}
+
+	list, err := cast.ToSliceE(raw)
+	if err != nil {
+		return reflect.Value{}, errors.Wrap(err, "expected list")
+	}
+
+	result := make(Contracts, 0, len(list))
+
+	for i, item := range list {
+		values, err := cast.ToStringMapE(item)
+		if err != nil {
+			return reflect.Value{}, errors.Wrap(err, "expected map", logan.F{
+				"index": i,
+			})
+		}
+
+		var contract Contract
+		if err := figure.Out(&contract).With(figure.BaseHooks, contractHooks).From(values).Please(); err != nil {
+			return reflect.Value{}, errors.Wrap(err, "failed to figure out contract", logan.F{
+				"index": i,
+			})
+		}
+
+		result = append(result, contract)
+	}
+
+	return reflect.ValueOf(result), nil
+}
@@ -3,79 +3,448 @@ package config
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/rarimo/evm-saver-svc/internal/services/cachedeth"
+	"github.com/rarimo/evm-saver-svc/internal/services/finality"
+	"github.com/rarimo/evm-saver-svc/internal/services/receiptproof"
+	"github.com/rarimo/evm-saver-svc/internal/services/rpcpool"
+	"github.com/rarimo/evm-saver-svc/internal/services/startblock"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/spf13/cast"
 	"gitlab.com/distributed_lab/figure"
 	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
+const (
+	// ModePolling makes listeners fetch logs with eth_getLogs periodically
+	ModePolling = "polling"
+	// ModeWebsocket makes listeners subscribe to new logs once they caught up with the chain
+	ModeWebsocket = "websocket"
+)
+
 type Ethereum struct {
-	ContractAddr common.Address    `fig:"contract_addr,required"`
-	RPCClient    *ethclient.Client `fig:"rpc,required"`
+	// ContractAddr is the bridge contract if it has never moved, otherwise it is the latest one of Contracts
+	ContractAddr common.Address `fig:"contract_addr"`
+	Contracts    Contracts      `fig:"contracts"`
+
+	// RPCEndpoints are a single url or a list of endpoints with priorities the requests fail over between
+	RPCEndpoints []rpcpool.Endpoint `fig:"rpc,required"`
+	RPCPool      rpcpool.Opts       `fig:"rpc_pool"`
+	// RPC and RPCClient send requests through the pool of RPCEndpoints
+	RPC       *rpc.Client     `fig:"-"`
+	RPCClient *rpcpool.Client `fig:"-"`
+
+	NetworkName string `fig:"network_name,required"`
+	Mode        string `fig:"mode"`
 
-	NetworkName    string `fig:"network_name,required"`
-	BlockWindow    uint64 `fig:"block_window,required"`
+	// Confirmation is the policy making deposit blocks final: block_window, safe or finalized
+	Confirmation   string `fig:"confirmation"`
+	BlockWindow    uint64 `fig:"block_window"`
 	StartFromBlock uint64 `fig:"start_from_block"`
 
+	// StartFromTime and StartFromDeployment resolve the start block of listeners without checkpoints
+	// instead of StartFromBlock
+	StartFromTime       *time.Time `fig:"start_from_time"`
+	StartFromDeployment bool       `fig:"start_from_deployment"`
+
+	// MaxEventAttempts is how many times in a row a deposit event is retried before it is set aside
+	MaxEventAttempts uint64 `fig:"max_event_attempts"`
+
+	// MinBlocksPerRequest and MaxBlocksPerRequest bound the adaptive eth_getLogs block range
+	MinBlocksPerRequest uint64 `fig:"min_blocks_per_request"`
+	MaxBlocksPerRequest uint64 `fig:"max_blocks_per_request"`
+
+	// CatchUpWorkers is how many windows are fetched concurrently when listeners are far behind
+	CatchUpWorkers uint64 `fig:"catchup_workers"`
+
+	// ReorgHistory is how many last processed windows are remembered to find the fork point of a reorg
+	ReorgHistory uint64 `fig:"reorg_history"`
+
+	Listeners Listeners `fig:"listeners"`
+
+	// TxCache bounds the cache of transactions and receipts from final blocks
+	TxCache TxCache `fig:"tx_cache"`
+
+	// Quorum lists independent providers the voter checks deposit logs with
+	Quorum          Quorum           `fig:"quorum"`
+	QuorumProviders []QuorumProvider `fig:"-"`
+
+	// ReceiptProofs sets how deposit receipts are proven, Prover is nil if they are not
+	ReceiptProofs ReceiptProofs        `fig:"receipt_proofs"`
+	Prover        *receiptproof.Prover `fig:"-"`
+
+	// ForceStartFromBlock makes listeners ignore saved checkpoints and start from StartFromBlock
+	ForceStartFromBlock bool `fig:"-"`
+
 	TxProvider *cachedeth.Provider `fig:"-"`
+	Finality   *finality.Source    `fig:"-"`
 }
 
-func (c *config) Ethereum() *Ethereum {
-	return c.ethereum.Do(func() interface{} {
-		cfg := Ethereum{}
+// TxCache is disabled if MaxSize is zero.
+type TxCache struct {
+	// MaxSize is the approximate size of cached values in bytes
+	MaxSize uint64        `fig:"max_size"`
+	TTL     time.Duration `fig:"ttl"`
+}
 
-		err := figure.
-			Out(&cfg).
-			With(figure.BaseHooks, evmHooks).
-			From(kv.MustGetStringMap(c.getter, "evm")).
-			Please()
-		if err != nil {
-			panic(errors.Wrap(err, "failed to figure out evm config"))
+// Listeners switches deposit listeners on and off by token type. All of them are enabled by default.
+type Listeners struct {
+	Native  bool `fig:"native"`
+	ERC20   bool `fig:"erc20"`
+	ERC721  bool `fig:"erc721"`
+	ERC1155 bool `fig:"erc1155"`
+}
+
+// Networks returns the EVM networks to listen to. Entries of evm.networks inherit the rest of the evm
+// section, so shared options are set once. The evm section itself is the only network if the list is
+// missing.
+func (c *config) Networks() []*Ethereum {
+	return c.networks.Do(func() interface{} {
+		raw := kv.MustGetStringMap(c.getter, "evm")
+
+		rawNetworks, ok := raw["networks"]
+		if !ok {
+			return []*Ethereum{c.figureNetwork(raw)}
 		}
 
-		cfg.TxProvider, err = cachedeth.NewProvider(c.Log(), cfg.RPCClient)
+		list, err := cast.ToSliceE(rawNetworks)
 		if err != nil {
-			panic(errors.Wrap(err, "failed to init tx provider"))
+			panic(errors.Wrap(err, "expected evm networks list"))
 		}
 
-		if cfg.StartFromBlock == 0 {
-			block, err := cfg.RPCClient.BlockNumber(context.TODO())
+		if len(list) == 0 {
+			panic(errors.New("evm networks list must not be empty"))
+		}
+
+		networks := make([]*Ethereum, 0, len(list))
+		names := make(map[string]struct{}, len(list))
+
+		for i, item := range list {
+			overrides, err := cast.ToStringMapE(item)
 			if err != nil {
-				panic(errors.Wrap(err, "failed to fetch last block"))
+				panic(errors.Wrap(err, "expected evm network map", logan.F{
+					"index": i,
+				}))
+			}
+
+			merged := make(map[string]interface{}, len(raw)+len(overrides))
+			for k, v := range raw {
+				if k != "networks" {
+					merged[k] = v
+				}
+			}
+			for k, v := range overrides {
+				merged[k] = v
 			}
 
-			cfg.StartFromBlock = block
+			network := c.figureNetwork(merged)
+			if _, ok := names[network.NetworkName]; ok {
+				panic(errors.From(errors.New("duplicated evm network"), logan.F{
+					"network": network.NetworkName,
+				}))
+			}
+
+			names[network.NetworkName] = struct{}{}
+			networks = append(networks, network)
+		}
+
+		return networks
+	}).([]*Ethereum)
+}
+
+// Network returns the configured network by name, nil if there is no such network.
+func (c *config) Network(name string) *Ethereum {
+	for _, network := range c.Networks() {
+		if network.NetworkName == name {
+			return network
+		}
+	}
+
+	return nil
+}
+
+func (c *config) figureNetwork(raw map[string]interface{}) *Ethereum {
+	cfg := Ethereum{
+		Mode:                ModePolling,
+		Confirmation:        finality.BlockWindow,
+		MaxEventAttempts:    10,
+		MinBlocksPerRequest: 10,
+		MaxBlocksPerRequest: 5000,
+		CatchUpWorkers:      1,
+		ReorgHistory:        128,
+		Listeners: Listeners{
+			Native:  true,
+			ERC20:   true,
+			ERC721:  true,
+			ERC1155: true,
+		},
+		TxCache: TxCache{
+			MaxSize: 32 << 20,
+			TTL:     time.Hour,
+		},
+		RPCPool: rpcpool.Opts{
+			ProbeInterval: 10 * time.Second,
+			Timeout:       30 * time.Second,
+			MaxFailures:   3,
+			MaxHeadLag:    10,
+		},
+		ReceiptProofs: ReceiptProofs{
+			MaxCheckpointDistance: 100000,
+		},
+	}
+
+	err := figure.
+		Out(&cfg).
+		With(figure.BaseHooks, evmHooks).
+		From(raw).
+		Please()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to figure out evm config"))
+	}
+
+	if cfg.Mode != ModePolling && cfg.Mode != ModeWebsocket {
+		panic(errors.From(errors.New("unknown evm listening mode"), logan.F{
+			"mode": cfg.Mode,
+		}))
+	}
+
+	if !finality.IsValidPolicy(cfg.Confirmation) {
+		panic(errors.From(errors.New("unknown evm confirmation policy"), logan.F{
+			"confirmation": cfg.Confirmation,
+		}))
+	}
+
+	if cfg.MaxEventAttempts == 0 {
+		panic(errors.New("max event attempts must be positive"))
+	}
+
+	// zero confirmations would make every head block final, so the window has to be set explicitly
+	if cfg.Confirmation == finality.BlockWindow && cfg.BlockWindow == 0 {
+		panic(errors.New("block_window must be positive with block_window confirmation"))
+	}
+
+	if cfg.MinBlocksPerRequest == 0 || cfg.MinBlocksPerRequest > cfg.MaxBlocksPerRequest {
+		panic(errors.From(errors.New("invalid blocks per request bounds"), logan.F{
+			"min_blocks_per_request": cfg.MinBlocksPerRequest,
+			"max_blocks_per_request": cfg.MaxBlocksPerRequest,
+		}))
+	}
+
+	if cfg.CatchUpWorkers == 0 {
+		panic(errors.New("catch-up workers amount must be positive"))
+	}
+
+	if cfg.ReorgHistory == 0 {
+		panic(errors.New("reorg history must not be empty"))
+	}
+
+	if err := cfg.resolveContracts(); err != nil {
+		panic(errors.Wrap(err, "invalid bridge contracts", logan.F{
+			"network": cfg.NetworkName,
+		}))
+	}
+
+	log := c.Log().WithField("network", cfg.NetworkName)
+
+	pool, err := rpcpool.New(c.ctx, log, cfg.NetworkName, cfg.RPCEndpoints, cfg.RPCPool)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to init rpc pool", logan.F{
+			"network": cfg.NetworkName,
+		}))
+	}
+
+	if cfg.Mode == ModeWebsocket && !pool.HasWebsocket() {
+		panic(errors.From(errors.New("websocket mode requires a websocket rpc endpoint"), logan.F{
+			"network": cfg.NetworkName,
+		}))
+	}
+
+	cfg.RPC = pool.RPC()
+	cfg.RPCClient = pool.Client()
+
+	if err := cfg.resolveQuorum(c.ctx, log); err != nil {
+		panic(errors.Wrap(err, "invalid quorum", logan.F{
+			"network": cfg.NetworkName,
+		}))
+	}
+
+	if err := cfg.resolveReceiptProofs(c.ctx, log); err != nil {
+		panic(errors.Wrap(err, "invalid receipt proofs", logan.F{
+			"network": cfg.NetworkName,
+		}))
+	}
+
+	cfg.Finality = finality.NewSource(cfg.Confirmation, cfg.BlockWindow, cfg.RPC)
+
+	cfg.TxProvider, err = cachedeth.NewProvider(log, cfg.NetworkName, cfg.RPC, cfg.Finality, cachedeth.CacheOpts{
+		MaxSize: cfg.TxCache.MaxSize,
+		TTL:     cfg.TxCache.TTL,
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to init tx provider"))
+	}
+
+	options := 0
+	for _, set := range []bool{cfg.StartFromBlock != 0, cfg.StartFromTime != nil, cfg.StartFromDeployment} {
+		if set {
+			options++
+		}
+	}
+
+	if options > 1 {
+		panic(errors.From(errors.New("only one of start_from_block, start_from_time and start_from_deployment can be set"), logan.F{
+			"network": cfg.NetworkName,
+		}))
+	}
+
+	return &cfg
+}
+
+// ResolveStartBlock returns the block listeners without checkpoints start from, the current block is
+// used if none of the start options is set. Resolving may take many requests, so it is done only once
+// a listener needs it.
+func (e *Ethereum) ResolveStartBlock(ctx context.Context, log *logan.Entry) (uint64, error) {
+	switch {
+	case e.StartFromTime != nil:
+		block, err := startblock.ByTime(ctx, e.RPCClient, *e.StartFromTime)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to find block by time", logan.F{
+				"start_from_time": e.StartFromTime.Format(time.RFC3339),
+			})
+		}
+
+		log.WithField("start_from_time", e.StartFromTime.Format(time.RFC3339)).
+			Infof("Resolved start block %d by time", block)
+		return block, nil
+	case e.StartFromDeployment:
+		contract := e.Contracts[0].Address
+
+		block, err := startblock.ByDeployment(ctx, e.RPCClient, contract)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to find contract deployment block", logan.F{
+				"contract": contract,
+			})
+		}
+
+		log.WithField("contract", contract).
+			Infof("Resolved start block %d by contract deployment", block)
+		return block, nil
+	case e.StartFromBlock == 0:
+		block, err := e.RPCClient.BlockNumber(ctx)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to fetch last block")
 		}
 
-		return &cfg
-	}).(*Ethereum)
+		return block, nil
+	default:
+		return e.StartFromBlock, nil
+	}
 }
 
 var evmHooks = figure.Hooks{
-	"common.Address": func(raw interface{}) (reflect.Value, error) {
-		v, err := cast.ToStringE(raw)
+	"*time.Time": func(raw interface{}) (reflect.Value, error) {
+		if raw == nil {
+			return reflect.ValueOf((*time.Time)(nil)), nil
+		}
+
+		switch v := raw.(type) {
+		case time.Time:
+			return reflect.ValueOf(&v), nil
+		case string:
+			if v == "" {
+				return reflect.ValueOf((*time.Time)(nil)), nil
+			}
+
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return reflect.Value{}, errors.Wrap(err, "expected RFC3339 time")
+			}
+
+			return reflect.ValueOf(&t), nil
+		default:
+			return reflect.Value{}, errors.Errorf("unsupported time type %T", raw)
+		}
+	},
+	"common.Address":   addressHook,
+	"config.Contracts": contractsHook,
+	"*receiptproof.Checkpoint": func(raw interface{}) (reflect.Value, error) {
+		if raw == nil {
+			return reflect.ValueOf((*receiptproof.Checkpoint)(nil)), nil
+		}
+
+		values, err := cast.ToStringMapE(raw)
 		if err != nil {
-			return reflect.Value{}, errors.Wrap(err, "expected string")
+			return reflect.Value{}, errors.Wrap(err, "expected checkpoint map")
+		}
+
+		var checkpoint receiptproof.Checkpoint
+		if err := figure.Out(&checkpoint).With(figure.BaseHooks, hashHooks).From(values).Please(); err != nil {
+			return reflect.Value{}, errors.Wrap(err, "failed to figure out checkpoint")
 		}
 
-		return reflect.ValueOf(common.HexToAddress(v)), nil
+		return reflect.ValueOf(&checkpoint), nil
 	},
-	"*ethclient.Client": func(raw interface{}) (reflect.Value, error) {
+	"[]rpcpool.Endpoint": func(raw interface{}) (reflect.Value, error) {
+		if v, ok := raw.(string); ok {
+			return reflect.ValueOf([]rpcpool.Endpoint{{URL: v}}), nil
+		}
+
+		list, err := cast.ToSliceE(raw)
+		if err != nil {
+			return reflect.Value{}, errors.Wrap(err, "expected url or list of endpoints")
+		}
+
+		endpoints := make([]rpcpool.Endpoint, len(list))
+
+		for i, item := range list {
+			if v, ok := item.(string); ok {
+				endpoints[i] = rpcpool.Endpoint{URL: v}
+				continue
+			}
+
+			values, err := cast.ToStringMapE(item)
+			if err != nil {
+				return reflect.Value{}, errors.Wrap(err, "expected url or endpoint map", logan.F{
+					"index": i,
+				})
+			}
+
+			if err := figure.Out(&endpoints[i]).With(figure.BaseHooks).From(values).Please(); err != nil {
+				return reflect.Value{}, errors.Wrap(err, "failed to figure out endpoint", logan.F{
+					"index": i,
+				})
+			}
+		}
+
+		return reflect.ValueOf(endpoints), nil
+	},
+}
+
+var hashHooks = figure.Hooks{
+	"common.Hash": func(raw interface{}) (reflect.Value, error) {
 		v, err := cast.ToStringE(raw)
 		if err != nil {
 			return reflect.Value{}, errors.Wrap(err, "expected string")
 		}
 
-		client, err := ethclient.Dial(v)
-		if err != nil {
-			return reflect.Value{}, errors.Wrap(err, "failed to dial eth rpc")
+		if len(common.FromHex(v)) != common.HashLength {
+			return reflect.Value{}, errors.Errorf("expected %d bytes hash", common.HashLength)
 		}
 
-		return reflect.ValueOf(client), nil
+		return reflect.ValueOf(common.HexToHash(v)), nil
 	},
 }
+
+func addressHook(raw interface{}) (reflect.Value, error) {
+	v, err := cast.ToStringE(raw)
+	if err != nil {
+		return reflect.Value{}, errors.Wrap(err, "expected string")
+	}
+
+	return reflect.ValueOf(common.HexToAddress(v)), nil
+}
@@ -1,6 +1,13 @@
 package config
 
 import (
+	"context"
+
+	"github.com/rarimo/evm-saver-svc/internal/services/cachedeth"
+	"github.com/rarimo/evm-saver-svc/internal/services/policy"
+	"github.com/rarimo/evm-saver-svc/internal/services/progress"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
 	"github.com/rarimo/saver-grpc-lib/broadcaster"
 	"github.com/rarimo/saver-grpc-lib/metrics"
 	"github.com/rarimo/saver-grpc-lib/voter"
@@ -17,9 +24,16 @@ type Config interface {
 	voter.Subscriberer
 	metrics.Profilerer
 
-	Ethereum() *Ethereum
+	Networks() []*Ethereum
+	Network(name string) *Ethereum
 	Cosmos() *grpc.ClientConn
 	Tendermint() *http.HTTP
+	Storage() *storage.Storage
+	Policy() *policy.Policy
+	Progress() *progress.Registry
+	TxStore() *cachedeth.Store
+	SaverAPI() api.EvmSaverClient
+	Status() Status
 }
 
 type config struct {
@@ -29,15 +43,24 @@ type config struct {
 	voter.Subscriberer
 	metrics.Profilerer
 
-	ethereum   comfig.Once
+	networks   comfig.Once
 	cosmos     comfig.Once
 	tendermint comfig.Once
+	storage    comfig.Once
+	policy     comfig.Once
+	progress   comfig.Once
+	status     comfig.Once
+	txstore    comfig.Once
+	saverAPI   comfig.Once
 
+	// ctx bounds routines the config starts in the background, e.g. rpc endpoints probes
+	ctx    context.Context
 	getter kv.Getter
 }
 
-func New(getter kv.Getter) Config {
+func New(ctx context.Context, getter kv.Getter) Config {
 	return &config{
+		ctx:           ctx,
 		getter:        getter,
 		Logger:        comfig.NewLogger(getter, comfig.LoggerOpts{}),
 		Listenerer:    comfig.NewListenerer(getter),
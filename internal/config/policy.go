@@ -0,0 +1,66 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/rarimo/evm-saver-svc/internal/services/policy"
+	"github.com/spf13/cast"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Policy returns deposit filtering rules, everything is allowed if the policy section is missing.
+func (c *config) Policy() *policy.Policy {
+	return c.policy.Do(func() interface{} {
+		var rules policy.Rules
+
+		err := figure.
+			Out(&rules).
+			With(figure.BaseHooks, policyHooks).
+			From(kv.MustGetStringMap(c.getter, "policy")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out policy config"))
+		}
+
+		p, err := policy.New(rules)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to init policy"))
+		}
+
+		return p
+	}).(*policy.Policy)
+}
+
+var policyHooks = figure.Hooks{
+	"map[string]policy.AmountLimits": func(raw interface{}) (reflect.Value, error) {
+		tokens, err := cast.ToStringMapE(raw)
+		if err != nil {
+			return reflect.Value{}, errors.Wrap(err, "expected map")
+		}
+
+		result := make(map[string]policy.AmountLimits, len(tokens))
+
+		for token, rawLimits := range tokens {
+			values, err := cast.ToStringMapE(rawLimits)
+			if err != nil {
+				return reflect.Value{}, errors.Wrap(err, "expected map", logan.F{
+					"token": token,
+				})
+			}
+
+			var limits policy.AmountLimits
+			if err := figure.Out(&limits).With(figure.BaseHooks).From(values).Please(); err != nil {
+				return reflect.Value{}, errors.Wrap(err, "failed to figure out amount limits", logan.F{
+					"token": token,
+				})
+			}
+
+			result[token] = limits
+		}
+
+		return reflect.ValueOf(result), nil
+	},
+}
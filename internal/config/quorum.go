@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+
+	"github.com/rarimo/evm-saver-svc/internal/services/rpcpool"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Quorum makes the voter check deposit logs with independent providers before voting, it is disabled
+// without providers.
+type Quorum struct {
+	RPC []rpcpool.Endpoint `fig:"rpc"`
+	// MinAgree is how many providers have to return the same log, all of them by default
+	MinAgree uint64 `fig:"min_agree"`
+}
+
+// QuorumProvider is an independent rpc deposit logs are checked with.
+type QuorumProvider struct {
+	Name   string
+	Client *rpcpool.Client
+}
+
+func (e *Ethereum) resolveQuorum(ctx context.Context, log *logan.Entry) error {
+	if len(e.Quorum.RPC) == 0 {
+		if e.Quorum.MinAgree != 0 {
+			return errors.New("quorum min_agree requires quorum providers")
+		}
+
+		return nil
+	}
+
+	if e.Quorum.MinAgree == 0 {
+		e.Quorum.MinAgree = uint64(len(e.Quorum.RPC))
+	}
+
+	if e.Quorum.MinAgree > uint64(len(e.Quorum.RPC)) {
+		return errors.From(errors.New("quorum min_agree exceeds the amount of providers"), logan.F{
+			"min_agree": e.Quorum.MinAgree,
+			"providers": len(e.Quorum.RPC),
+		})
+	}
+
+	names := make(map[string]struct{}, len(e.Quorum.RPC))
+
+	for _, endpoint := range e.Quorum.RPC {
+		name := endpoint.Label()
+		if _, ok := names[name]; ok {
+			return errors.From(errors.New("duplicated quorum provider name, set names explicitly"), logan.F{
+				"provider": name,
+			})
+		}
+
+		names[name] = struct{}{}
+
+		// every provider is a pool of its own, requests must not fail over to another provider
+		pool, err := rpcpool.New(ctx, log.WithField("quorum_provider", name), e.NetworkName, []rpcpool.Endpoint{endpoint}, e.RPCPool)
+		if err != nil {
+			return errors.Wrap(err, "failed to init quorum provider", logan.F{
+				"provider": name,
+			})
+		}
+
+		e.QuorumProviders = append(e.QuorumProviders, QuorumProvider{Name: name, Client: pool.Client()})
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"context"
+
+	"github.com/rarimo/evm-saver-svc/internal/services/receiptproof"
+	"github.com/rarimo/evm-saver-svc/internal/services/rpcpool"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// ReceiptProofs makes the voter prove deposit receipts against canonical block headers. It is disabled
+// unless either HeaderRPC or Checkpoint is set.
+type ReceiptProofs struct {
+	// HeaderRPC is trusted to tell canonical block hashes, it should not be the one receipts come from
+	HeaderRPC  []rpcpool.Endpoint       `fig:"header_rpc"`
+	Checkpoint *receiptproof.Checkpoint `fig:"checkpoint"`
+	// MaxCheckpointDistance is how far below the checkpoint deposits can be proven
+	MaxCheckpointDistance uint64 `fig:"max_checkpoint_distance"`
+}
+
+func (e *Ethereum) resolveReceiptProofs(ctx context.Context, log *logan.Entry) error {
+	var headers receiptproof.HeaderSource
+
+	switch {
+	case len(e.ReceiptProofs.HeaderRPC) != 0 && e.ReceiptProofs.Checkpoint != nil:
+		return errors.New("only one of header_rpc and checkpoint can be set")
+	case len(e.ReceiptProofs.HeaderRPC) != 0:
+		pool, err := rpcpool.New(ctx, log.WithField("rpc", "header"), e.NetworkName, e.ReceiptProofs.HeaderRPC, e.RPCPool)
+		if err != nil {
+			return errors.Wrap(err, "failed to init header rpc pool")
+		}
+
+		headers = receiptproof.NewRPCHeaders(pool.RPC())
+	case e.ReceiptProofs.Checkpoint != nil:
+		if e.ReceiptProofs.MaxCheckpointDistance == 0 {
+			return errors.New("max_checkpoint_distance must be positive")
+		}
+
+		log.WithFields(logan.F{
+			"checkpoint":      e.ReceiptProofs.Checkpoint.Number,
+			"checkpoint_hash": e.ReceiptProofs.Checkpoint.Hash,
+		}).Warn("Deposits after the checkpoint can not be proven, update it regularly")
+
+		headers = receiptproof.NewCheckpointHeaders(e.RPC, *e.ReceiptProofs.Checkpoint, e.ReceiptProofs.MaxCheckpointDistance)
+	default:
+		return nil
+	}
+
+	e.Prover = receiptproof.NewProver(e.NetworkName, e.RPC, headers)
+	return nil
+}
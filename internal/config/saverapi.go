@@ -0,0 +1,43 @@
+package config
+
+import (
+	"time"
+
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// SaverAPI is the client of the api of the saver run as a separate process, it is nil if the address
+// is empty. The saver state can be opened by a single process only, so the api run apart from the saver
+// reaches it through the saver.
+func (c *config) SaverAPI() api.EvmSaverClient {
+	client, _ := c.saverAPI.Do(func() interface{} {
+		var config struct {
+			Addr string `fig:"addr"`
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "saver_api")).Please(); err != nil {
+			panic(errors.Wrap(err, "failed to figure out saver api config"))
+		}
+
+		if config.Addr == "" {
+			return api.EvmSaverClient(nil)
+		}
+
+		con, err := grpc.Dial(config.Addr, grpc.WithInsecure(), grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    10 * time.Second, // wait time before ping if no activity
+			Timeout: 20 * time.Second, // ping timeout
+		}))
+		if err != nil {
+			panic(errors.Wrap(err, "failed to dial saver api"))
+		}
+
+		return api.NewEvmSaverClient(con)
+	}).(api.EvmSaverClient)
+
+	return client
+}
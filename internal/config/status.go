@@ -0,0 +1,32 @@
+package config
+
+import (
+	"github.com/rarimo/evm-saver-svc/internal/services/progress"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Status is the http server exposing listeners progress, it is disabled if the address is empty.
+type Status struct {
+	Addr string `fig:"addr"`
+}
+
+func (c *config) Status() Status {
+	return c.status.Do(func() interface{} {
+		var status Status
+
+		if err := figure.Out(&status).From(kv.MustGetStringMap(c.getter, "status")).Please(); err != nil {
+			panic(errors.Wrap(err, "failed to figure out status config"))
+		}
+
+		return status
+	}).(Status)
+}
+
+// Progress is shared by listeners and the api running in the same process.
+func (c *config) Progress() *progress.Registry {
+	return c.progress.Do(func() interface{} {
+		return progress.NewRegistry()
+	}).(*progress.Registry)
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Storage keeps the saver state. Only the process running the saver opens it, the api run as a separate
+// process reaches the state through the saver api.
+func (c *config) Storage() *storage.Storage {
+	return c.storage.Do(func() interface{} {
+		var config struct {
+			Path string `fig:"path,required"`
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "storage")).Please(); err != nil {
+			panic(errors.Wrap(err, "failed to figure out storage config"))
+		}
+
+		s, err := storage.New(config.Path)
+		if errors.Cause(err) == storage.ErrLocked {
+			panic(errors.Wrap(err, "failed to open storage, it is used by another saver"))
+		}
+
+		if err != nil {
+			panic(errors.Wrap(err, "failed to open storage"))
+		}
+
+		return s
+	}).(*storage.Storage)
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"github.com/rarimo/evm-saver-svc/internal/services/cachedeth"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// TxStore is the disk store of transactions and receipts shared by all networks, it is nil if the path
+// is empty. Only the voter reads it, so the store is open once the voter starts and other processes
+// do not lock it.
+func (c *config) TxStore() *cachedeth.Store {
+	return c.txstore.Do(func() interface{} {
+		opts := cachedeth.StoreOpts{
+			MaxSize: 1 << 30,
+		}
+
+		err := figure.
+			Out(&opts).
+			With(figure.BaseHooks).
+			From(kv.MustGetStringMap(c.getter, "tx_store")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out tx store config"))
+		}
+
+		if opts.Path == "" {
+			return (*cachedeth.Store)(nil)
+		}
+
+		store, err := cachedeth.OpenStore(opts)
+		if errors.Cause(err) == cachedeth.ErrLocked {
+			panic(errors.Wrap(err, "failed to open tx store, voters started as separate processes need their own tx_store.path"))
+		}
+
+		if err != nil {
+			panic(errors.Wrap(err, "failed to open tx store"))
+		}
+
+		return store
+	}).(*cachedeth.Store)
+}
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	gobind "github.com/rarimo/evm-bridge-contracts/gobind/contracts/interfaces/handlers"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+var ErrUnknownEvent = errors.New("unknown deposit event")
+
+// Parsing logs does not touch the chain, so filterers are bound to nothing.
+var (
+	erc20Filterer, _   = gobind.NewIERC20HandlerFilterer(common.Address{}, nil)
+	erc721Filterer, _  = gobind.NewIERC721HandlerFilterer(common.Address{}, nil)
+	erc1155Filterer, _ = gobind.NewIERC1155HandlerFilterer(common.Address{}, nil)
+	nativeFilterer, _  = gobind.NewINativeHandlerFilterer(common.Address{}, nil)
+)
+
+var (
+	IERC20DepositedTopic   = common.HexToHash("0x043d52f9acdd847f0210803c386559db9e09d492143f2072fe30ea62ff0bb639")
+	IERC721DepositedTopic  = common.HexToHash("0x7f787dd0c844dac4f8bfc4044046cdab3be531f7eefa9b740c531e48a99725e1")
+	IERC1155DepositedTopic = common.HexToHash("0x103b790f2fa3a8676ff87c3620a55f0853d0e45128a8c7e9fadf29e17c51d07a")
+	INativeDepositedTopic  = common.HexToHash("0x9a47c8733424880a9e86a368eff95da5e7d36b68474a95eb097be2e43c116f27")
+)
+
+// Decode parses a raw bridge deposit log into the matching Event.
+func Decode(log types.Log) (Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, errors.Wrap(ErrUnknownEvent, "log has no topics")
+	}
+
+	switch log.Topics[0] {
+	case IERC20DepositedTopic:
+		e, err := erc20Filterer.ParseDepositedERC20(log)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse erc20 log")
+		}
+
+		return &IERC20Event{E: e}, nil
+	case IERC721DepositedTopic:
+		e, err := erc721Filterer.ParseDepositedERC721(log)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse erc721 log")
+		}
+
+		return &IERC721Event{E: e}, nil
+	case IERC1155DepositedTopic:
+		e, err := erc1155Filterer.ParseDepositedERC1155(log)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse erc1155 log")
+		}
+
+		return &IERC1155Event{E: e}, nil
+	case INativeDepositedTopic:
+		e, err := nativeFilterer.ParseDepositedNative(log)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse native log")
+		}
+
+		return &INativeEvent{E: e}, nil
+	default:
+		return nil, errors.From(ErrUnknownEvent, logan.F{
+			"topic": log.Topics[0].Hex(),
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package events
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Version1 is the event set of the handlers the bindings are generated for. Bridge versions emitting
+// other events get their own entry together with decoding support.
+const Version1 = "v1"
+
+var versions = map[string]map[common.Hash]struct{}{
+	Version1: {
+		IERC20DepositedTopic:   {},
+		IERC721DepositedTopic:  {},
+		IERC1155DepositedTopic: {},
+		INativeDepositedTopic:  {},
+	},
+}
+
+// IsKnownVersion reports whether events of the bridge version can be decoded.
+func IsKnownVersion(version string) bool {
+	_, ok := versions[version]
+	return ok
+}
+
+// HasTopic reports whether the bridge version emits deposit events with the topic.
+func HasTopic(version string, topic common.Hash) bool {
+	_, ok := versions[version][topic]
+	return ok
+}
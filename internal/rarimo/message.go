@@ -17,6 +17,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// ErrDestinationItemNotFound means the deposited token is not registered on core for the destination
+// chain, so the transfer message can not be made until it is.
+var ErrDestinationItemNotFound = errors.New("destination on chain item not found")
+
 type EthTxProvider interface {
 	GetTx(ctx context.Context, hash common.Hash) (*types.Transaction, string, error)
 }
@@ -31,13 +35,14 @@ type MessageMaker struct {
 
 func NewMessageMaker(
 	cfg config.Config,
+	network *config.Ethereum,
 ) *MessageMaker {
 	return &MessageMaker{
-		log:              cfg.Log(),
+		log:              cfg.Log().WithField("network", network.NetworkName),
 		txCreatorAddr:    cfg.Broadcaster().Sender(),
-		homeChain:        cfg.Ethereum().NetworkName,
+		homeChain:        network.NetworkName,
 		tokenQueryClient: tokentypes.NewQueryClient(cfg.Cosmos()),
-		txProvider:       cfg.Ethereum().TxProvider,
+		txProvider:       network.TxProvider,
 	}
 }
 
@@ -106,7 +111,7 @@ func (m *MessageMaker) ensureDstItem(ctx context.Context, srcOnChainItem *tokent
 	})
 	if err != nil {
 		if res, ok := status.FromError(err); ok && res.Code() == codes.NotFound {
-			return nil, errors.Wrap(err, "destination onc chain item not found")
+			return nil, errors.Wrap(ErrDestinationItemNotFound, res.Message())
 		}
 
 		return nil, errors.Wrap(err, "failed to fetch destination on chain item")
@@ -0,0 +1,53 @@
+package rarimo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	rarimocore "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OperationChecker tells whether deposit events are already submitted to core.
+type OperationChecker struct {
+	homeChain       string
+	coreQueryClient rarimocore.QueryClient
+}
+
+func NewOperationChecker(cfg config.Config, network *config.Ethereum) *OperationChecker {
+	return &OperationChecker{
+		homeChain:       network.NetworkName,
+		coreQueryClient: rarimocore.NewQueryClient(cfg.Cosmos()),
+	}
+}
+
+// IsSubmitted reports whether core has the transfer operation of the event. Operations core has not
+// approved can be created again, so they are not considered submitted.
+func (c *OperationChecker) IsSubmitted(ctx context.Context, raw types.Log) (bool, error) {
+	index := TransferOperationIndex(raw.TxHash.String(), fmt.Sprintf("%d", raw.Index), c.homeChain)
+
+	resp, err := c.coreQueryClient.Operation(ctx, &rarimocore.QueryGetOperationRequest{Index: index})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "failed to get operation", logan.F{
+			"index": index,
+		})
+	}
+
+	return resp.Operation.Status != rarimocore.OpStatus_NOT_APPROVED, nil
+}
+
+// TransferOperationIndex is the index core gives to the transfer operation: HASH(tx, event, chain).
+func TransferOperationIndex(tx, eventId, chain string) string {
+	return hexutil.Encode(crypto.Keccak256([]byte(tx), []byte(eventId), []byte(chain)))
+}
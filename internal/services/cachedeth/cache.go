@@ -0,0 +1,94 @@
+package cachedeth
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cache is a least recently used cache bounded by the total size of its values. Entries expire after
+// the ttl regardless of how often they are used.
+type cache struct {
+	maxSize uint64
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	size    uint64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key       string
+	value     interface{}
+	size      uint64
+	expiresAt time.Time
+}
+
+func newCache(maxSize uint64, ttl time.Duration) *cache {
+	return &cache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *cache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(elem)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// put stores the value unless it alone is bigger than the cache, the least recently used entries are
+// evicted to make room for it.
+func (c *cache) put(key string, value interface{}, size uint64) {
+	if size > c.maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+
+	for c.size+size > c.maxSize {
+		c.remove(c.order.Back())
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:       key,
+		value:     value,
+		size:      size,
+		expiresAt: time.Now().Add(c.ttl),
+	})
+	c.size += size
+}
+
+func (c *cache) usedSize() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.size
+}
+
+func (c *cache) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
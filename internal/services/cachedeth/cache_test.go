@@ -0,0 +1,120 @@
+package cachedeth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachePut(t *testing.T) {
+	type put struct {
+		key  string
+		size uint64
+	}
+
+	cases := []struct {
+		name     string
+		maxSize  uint64
+		puts     []put
+		touch    string
+		wantKeys []string
+		wantGone []string
+		wantSize uint64
+	}{
+		{
+			name:     "values fitting the cache are kept",
+			maxSize:  10,
+			puts:     []put{{"a", 3}, {"b", 3}, {"c", 4}},
+			wantKeys: []string{"a", "b", "c"},
+			wantSize: 10,
+		},
+		{
+			name:     "least recently put is evicted",
+			maxSize:  10,
+			puts:     []put{{"a", 4}, {"b", 4}, {"c", 4}},
+			wantKeys: []string{"b", "c"},
+			wantGone: []string{"a"},
+			wantSize: 8,
+		},
+		{
+			name:     "recently read entry survives eviction",
+			maxSize:  10,
+			puts:     []put{{"a", 4}, {"b", 4}},
+			touch:    "a",
+			wantKeys: []string{"a"},
+			wantGone: []string{"b"},
+			wantSize: 8,
+		},
+		{
+			name:     "several entries are evicted for a big value",
+			maxSize:  10,
+			puts:     []put{{"a", 3}, {"b", 3}, {"c", 3}, {"d", 9}},
+			wantKeys: []string{"d"},
+			wantGone: []string{"a", "b", "c"},
+			wantSize: 9,
+		},
+		{
+			name:     "value bigger than the cache is not stored",
+			maxSize:  10,
+			puts:     []put{{"a", 3}, {"b", 11}},
+			wantKeys: []string{"a"},
+			wantGone: []string{"b"},
+			wantSize: 3,
+		},
+		{
+			name:     "replaced value is counted once",
+			maxSize:  10,
+			puts:     []put{{"a", 3}, {"b", 3}, {"a", 6}},
+			wantKeys: []string{"a", "b"},
+			wantSize: 9,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cache := newCache(c.maxSize, time.Hour)
+
+			for i, p := range c.puts {
+				cache.put(p.key, i, p.size)
+			}
+
+			if c.touch != "" {
+				if _, ok := cache.get(c.touch); !ok {
+					t.Fatalf("%s is missing before the last put", c.touch)
+				}
+
+				cache.put("last", nil, 4)
+			}
+
+			for _, key := range c.wantKeys {
+				if _, ok := cache.get(key); !ok {
+					t.Errorf("%s is evicted", key)
+				}
+			}
+
+			for _, key := range c.wantGone {
+				if _, ok := cache.get(key); ok {
+					t.Errorf("%s is kept", key)
+				}
+			}
+
+			if size := cache.usedSize(); size != c.wantSize {
+				t.Errorf("used size = %d, want %d", size, c.wantSize)
+			}
+		})
+	}
+}
+
+func TestCacheExpiration(t *testing.T) {
+	cache := newCache(10, time.Millisecond)
+	cache.put("a", 1, 5)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.get("a"); ok {
+		t.Error("expired entry is returned")
+	}
+
+	if size := cache.usedSize(); size != 0 {
+		t.Errorf("used size = %d after expiration, want 0", size)
+	}
+}
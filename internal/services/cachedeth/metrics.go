@@ -0,0 +1,44 @@
+package cachedeth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Kinds of cached values
+const (
+	kindTx      = "tx"
+	kindReceipt = "receipt"
+)
+
+var (
+	cacheHitsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_tx_cache_hits",
+		Help: "Number of transactions and receipts served from the cache",
+	}, []string{"network", "kind"})
+
+	cacheMissesMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_tx_cache_misses",
+		Help: "Number of transactions and receipts requested from the rpc",
+	}, []string{"network", "kind"})
+
+	cacheSizeMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "evm_tx_cache_size_bytes",
+		Help: "Approximate size of cached transactions and receipts",
+	}, []string{"network"})
+
+	storeHitsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_tx_store_hits",
+		Help: "Number of transactions and receipts served from the disk store",
+	}, []string{"network", "kind"})
+
+	storeMissesMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_tx_store_misses",
+		Help: "Number of transactions and receipts missing in the disk store",
+	}, []string{"network", "kind"})
+
+	storeSizeMetric = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "evm_tx_store_size_bytes",
+		Help: "Approximate size of transactions and receipts in the disk store",
+	})
+)
@@ -2,27 +2,93 @@ package cachedeth
 
 import (
 	"context"
+	"encoding/json"
+	"math/big"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rarimo/evm-saver-svc/internal/services/finality"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
+// Approximate in-memory sizes of a receipt and a log without their variable length parts
+const (
+	receiptOverhead = 512
+	logOverhead     = 192
+	senderSize      = 64
+)
+
+// CacheOpts bound the cache, it is disabled if MaxSize is zero.
+type CacheOpts struct {
+	MaxSize uint64
+	TTL     time.Duration
+}
+
+// Provider requests transactions and receipts of the network. Results from final blocks are cached,
+// since a reorg can not change them, and kept in the disk store if there is one.
 type Provider struct {
-	log    *logan.Entry
-	client *ethclient.Client
+	log      *logan.Entry
+	network  string
+	client   *ethclient.Client
+	rpc      *rpc.Client
+	finality *finality.Source
+	cache    *cache
+	store    *Store
 }
 
-func NewProvider(log *logan.Entry, client *ethclient.Client) (*Provider, error) {
-	return &Provider{
-		log:    log,
-		client: client,
-	}, nil
+func NewProvider(log *logan.Entry, network string, client *rpc.Client, finality *finality.Source, opts CacheOpts) (*Provider, error) {
+	p := &Provider{
+		log:      log,
+		network:  network,
+		client:   ethclient.NewClient(client),
+		rpc:      client,
+		finality: finality,
+	}
+
+	if opts.MaxSize > 0 {
+		if opts.TTL <= 0 {
+			return nil, errors.New("cache ttl must be positive")
+		}
+
+		p.cache = newCache(opts.MaxSize, opts.TTL)
+	}
+
+	return p, nil
+}
+
+// WithStore returns the provider sharing the cache with p that also keeps values in the store. Blocks
+// the store has values of are final, so they are not checked again after a restart.
+func (p *Provider) WithStore(store *Store) (*Provider, error) {
+	lastBlock, ok, err := store.LastBlock(p.network)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get last stored block")
+	}
+
+	if ok {
+		p.finality.Seen(lastBlock)
+	}
+
+	stored := *p
+	stored.store = store
+	return &stored, nil
 }
 
 func (p *Provider) GetTxReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if cached, ok := p.fromCache(kindReceipt, hash); ok {
+		return cached.(*types.Receipt), nil
+	}
+
+	if stored, ok := p.fromStore(kindReceipt, hash); ok && stored.Receipt != nil {
+		p.toCache(kindReceipt, hash, stored.Receipt, receiptSize(stored.Receipt))
+		return stored.Receipt, nil
+	}
+
 	liveReceipt, err := p.client.TransactionReceipt(ctx, hash)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get tx receipt by hash", logan.F{
@@ -30,11 +96,35 @@ func (p *Provider) GetTxReceipt(ctx context.Context, hash common.Hash) (*types.R
 		})
 	}
 
+	if p.isFinal(ctx, hash, liveReceipt.BlockNumber) {
+		p.toCache(kindReceipt, hash, liveReceipt, receiptSize(liveReceipt))
+		p.toStore(kindReceipt, hash, storedValue{
+			Block:   liveReceipt.BlockNumber.Uint64(),
+			Receipt: liveReceipt,
+		})
+	}
+
 	return liveReceipt, nil
 }
 
+type txWithSender struct {
+	tx     *types.Transaction
+	sender string
+}
+
 func (p *Provider) GetTx(ctx context.Context, hash common.Hash) (*types.Transaction, string, error) {
-	tx, _, err := p.client.TransactionByHash(ctx, hash)
+	if cached, ok := p.fromCache(kindTx, hash); ok {
+		v := cached.(txWithSender)
+		return v.tx, v.sender, nil
+	}
+
+	if stored, ok := p.fromStore(kindTx, hash); ok && stored.Tx != nil {
+		v := txWithSender{tx: stored.Tx, sender: stored.Sender}
+		p.toCache(kindTx, hash, v, uint64(v.tx.Size())+senderSize)
+		return v.tx, v.sender, nil
+	}
+
+	tx, block, err := p.getTx(ctx, hash)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "failed to get tx by hash", logan.F{
 			"hash": hash,
@@ -48,5 +138,125 @@ func (p *Provider) GetTx(ctx context.Context, hash common.Hash) (*types.Transact
 		})
 	}
 
-	return tx, sender.String(), nil
+	v := txWithSender{tx: tx, sender: sender.String()}
+
+	if p.isFinal(ctx, hash, block) {
+		p.toCache(kindTx, hash, v, uint64(tx.Size())+senderSize)
+		p.toStore(kindTx, hash, storedValue{
+			Block:  block.Uint64(),
+			Tx:     tx,
+			Sender: v.sender,
+		})
+	}
+
+	return v.tx, v.sender, nil
+}
+
+// getTx requests the transaction together with its block number, which ethclient does not expose. The
+// block is nil for pending transactions.
+func (p *Provider) getTx(ctx context.Context, hash common.Hash) (*types.Transaction, *big.Int, error) {
+	var raw json.RawMessage
+	if err := p.rpc.CallContext(ctx, &raw, "eth_getTransactionByHash", hash); err != nil {
+		return nil, nil, err
+	}
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil, ethereum.NotFound
+	}
+
+	tx := new(types.Transaction)
+	if err := json.Unmarshal(raw, tx); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal tx")
+	}
+
+	var meta struct {
+		BlockNumber *hexutil.Big `json:"blockNumber"`
+	}
+
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal tx block")
+	}
+
+	return tx, (*big.Int)(meta.BlockNumber), nil
+}
+
+func (p *Provider) fromCache(kind string, hash common.Hash) (interface{}, bool) {
+	if p.cache == nil {
+		return nil, false
+	}
+
+	value, ok := p.cache.get(kind + "/" + hash.Hex())
+	if ok {
+		cacheHitsMetric.WithLabelValues(p.network, kind).Inc()
+	} else {
+		cacheMissesMetric.WithLabelValues(p.network, kind).Inc()
+	}
+
+	return value, ok
+}
+
+func (p *Provider) toCache(kind string, hash common.Hash, value interface{}, size uint64) {
+	if p.cache == nil {
+		return
+	}
+
+	p.cache.put(kind+"/"+hash.Hex(), value, size)
+	cacheSizeMetric.WithLabelValues(p.network).Set(float64(p.cache.usedSize()))
+}
+
+// fromStore falls back to the rpc if the store fails, it is only a cache.
+func (p *Provider) fromStore(kind string, hash common.Hash) (*storedValue, bool) {
+	if p.store == nil {
+		return nil, false
+	}
+
+	value, ok, err := p.store.get(p.network, kind, hash)
+	if err != nil {
+		p.log.WithError(err).WithField("hash", hash).Warn("failed to get value from the store")
+		return nil, false
+	}
+
+	if ok {
+		storeHitsMetric.WithLabelValues(p.network, kind).Inc()
+	} else {
+		storeMissesMetric.WithLabelValues(p.network, kind).Inc()
+	}
+
+	return value, ok
+}
+
+func (p *Provider) toStore(kind string, hash common.Hash, value storedValue) {
+	if p.store == nil {
+		return
+	}
+
+	if err := p.store.put(p.network, kind, hash, value); err != nil {
+		p.log.WithError(err).WithField("hash", hash).Warn("failed to put value to the store")
+	}
+}
+
+// isFinal tells whether values of the block can be kept. Failing to check it only makes the value
+// uncached, pending transactions have no block.
+func (p *Provider) isFinal(ctx context.Context, hash common.Hash, block *big.Int) bool {
+	if block == nil || (p.cache == nil && p.store == nil) {
+		return false
+	}
+
+	final, err := p.finality.IsFinal(ctx, block.Uint64())
+	if err != nil {
+		p.log.WithError(err).WithField("hash", hash).Debug("failed to check block finality, not caching")
+		return false
+	}
+
+	return final
+}
+
+func receiptSize(receipt *types.Receipt) uint64 {
+	size := uint64(receiptOverhead)
+
+	for _, log := range receipt.Logs {
+		size += logOverhead + uint64(len(log.Data)) + uint64(len(log.Topics))*common.HashLength
+	}
+
+	return size
 }
@@ -0,0 +1,242 @@
+package cachedeth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	storeValuePrefix = "value"
+	storeBlockPrefix = "block"
+)
+
+// ErrLocked means the store is open by another process, leveldb can not be shared between processes.
+var ErrLocked = errors.New("tx store is locked by another process")
+
+// StoreOpts configure the disk store, values are never pruned by age if RetentionBlocks is zero.
+type StoreOpts struct {
+	Path string `fig:"path"`
+	// MaxSize is the approximate size of stored values in bytes
+	MaxSize         uint64 `fig:"max_size"`
+	RetentionBlocks uint64 `fig:"retention_blocks"`
+}
+
+// Store keeps transactions and receipts from final blocks on disk, so they are not requested again
+// after a restart. Values are indexed by block: blocks older than the retention are pruned, and the
+// oldest blocks of the network being written go first once the store is full. The store is a cache,
+// losing it only costs requests.
+type Store struct {
+	db        *leveldb.DB
+	maxSize   uint64
+	retention uint64
+
+	mu   sync.Mutex
+	size uint64
+}
+
+type storedValue struct {
+	Block   uint64             `json:"block"`
+	Receipt *types.Receipt     `json:"receipt,omitempty"`
+	Tx      *types.Transaction `json:"tx,omitempty"`
+	Sender  string             `json:"sender,omitempty"`
+}
+
+func OpenStore(opts StoreOpts) (*Store, error) {
+	if opts.MaxSize == 0 {
+		return nil, errors.New("store max size must be positive")
+	}
+
+	db, err := leveldb.OpenFile(opts.Path, nil)
+	if err == syscall.EWOULDBLOCK {
+		return nil, errors.From(ErrLocked, logan.F{
+			"path": opts.Path,
+		})
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open leveldb", logan.F{
+			"path": opts.Path,
+		})
+	}
+
+	s := &Store{
+		db:        db,
+		maxSize:   opts.MaxSize,
+		retention: opts.RetentionBlocks,
+	}
+
+	// the size is measured instead of being persisted, so it can not drift after a crash
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		s.size += uint64(len(iter.Key()) + len(iter.Value()))
+	}
+
+	if err := iter.Error(); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "failed to measure store")
+	}
+
+	storeSizeMetric.Set(float64(s.size))
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// LastBlock returns the latest block the network has values of, it is known to be final.
+func (s *Store) LastBlock(network string) (uint64, bool, error) {
+	prefix := storeBlockNetworkPrefix(network)
+
+	iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	if !iter.Last() {
+		return 0, false, errors.Wrap(iter.Error(), "failed to iterate store blocks")
+	}
+
+	return binary.BigEndian.Uint64(iter.Key()[len(prefix):]), true, nil
+}
+
+func (s *Store) get(network, kind string, hash common.Hash) (*storedValue, bool, error) {
+	raw, err := s.db.Get(storeValueKey(network, kind, hash), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get stored value")
+	}
+
+	var value storedValue
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, false, errors.Wrap(err, "failed to unmarshal stored value")
+	}
+
+	return &value, true, nil
+}
+
+// put stores the value of the final block and prunes values that are out of the retention or do not
+// fit anymore.
+func (s *Store) put(network, kind string, hash common.Hash, value storedValue) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal stored value")
+	}
+
+	valueKey := storeValueKey(network, kind, hash)
+	blockKey := storeBlockKey(network, value.Block, kind, hash)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	has, err := s.db.Has(valueKey, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to check stored value")
+	}
+
+	// values of final blocks never change, so there is nothing to rewrite
+	if has {
+		return nil
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put(valueKey, raw)
+	batch.Put(blockKey, nil)
+
+	if err := s.db.Write(batch, nil); err != nil {
+		return errors.Wrap(err, "failed to write stored value")
+	}
+
+	s.size += uint64(len(valueKey) + len(raw) + len(blockKey))
+
+	if s.retention > 0 && value.Block > s.retention {
+		if err := s.prune(network, func(block uint64) bool {
+			return block < value.Block-s.retention
+		}); err != nil {
+			return errors.Wrap(err, "failed to prune blocks out of retention")
+		}
+	}
+
+	if s.size > s.maxSize {
+		if err := s.prune(network, func(uint64) bool {
+			return s.size > s.maxSize
+		}); err != nil {
+			return errors.Wrap(err, "failed to prune oldest blocks")
+		}
+	}
+
+	storeSizeMetric.Set(float64(s.size))
+	return nil
+}
+
+// prune deletes values of the network from the oldest block while the condition holds for it.
+func (s *Store) prune(network string, cond func(block uint64) bool) error {
+	prefix := storeBlockNetworkPrefix(network)
+
+	iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		key := iter.Key()
+		if !cond(binary.BigEndian.Uint64(key[len(prefix):])) {
+			break
+		}
+
+		// the block key ends with the kind and hash of the value, see storeBlockKey
+		kindHash := bytes.SplitN(key[len(prefix)+8:], []byte("/"), 2)
+		valueKey := storeValueKey(network, string(kindHash[0]), common.BytesToHash(kindHash[1]))
+
+		raw, err := s.db.Get(valueKey, nil)
+		if err != nil && err != leveldb.ErrNotFound {
+			return errors.Wrap(err, "failed to get pruned value")
+		}
+
+		batch := new(leveldb.Batch)
+		batch.Delete(valueKey)
+		batch.Delete(key)
+
+		if err := s.db.Write(batch, nil); err != nil {
+			return errors.Wrap(err, "failed to delete pruned value")
+		}
+
+		freed := uint64(len(valueKey) + len(raw) + len(key))
+		if freed > s.size {
+			freed = s.size
+		}
+
+		s.size -= freed
+	}
+
+	return errors.Wrap(iter.Error(), "failed to iterate store blocks")
+}
+
+func storeValueKey(network, kind string, hash common.Hash) []byte {
+	return append([]byte(fmt.Sprintf("%s/%s/%s/", storeValuePrefix, network, kind)), hash.Bytes()...)
+}
+
+func storeBlockNetworkPrefix(network string) []byte {
+	return []byte(fmt.Sprintf("%s/%s/", storeBlockPrefix, network))
+}
+
+// storeBlockKey orders values of the network by block, the big endian block number keeps the order.
+func storeBlockKey(network string, block uint64, kind string, hash common.Hash) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, block)
+
+	key = append(storeBlockNetworkPrefix(network), key...)
+	key = append(key, kind+"/"...)
+	return append(key, hash.Bytes()...)
+}
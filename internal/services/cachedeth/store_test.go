@@ -0,0 +1,155 @@
+package cachedeth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestStorePrune(t *testing.T) {
+	type value struct {
+		network string
+		block   uint64
+		// hash is a byte of the value hash, 0x2f is the key separator
+		hash byte
+	}
+
+	cases := []struct {
+		name      string
+		retention uint64
+		// maxValues limits the store size in values of the test size
+		maxValues uint64
+		values    []value
+		wantKept  []value
+		wantGone  []value
+	}{
+		{
+			name:      "values out of retention are pruned",
+			retention: 10,
+			maxValues: 100,
+			values:    []value{{"eth", 1, 1}, {"eth", 5, 2}, {"eth", 12, 3}, {"eth", 20, 4}},
+			wantKept:  []value{{"eth", 12, 3}, {"eth", 20, 4}},
+			wantGone:  []value{{"eth", 1, 1}, {"eth", 5, 2}},
+		},
+		{
+			name:      "retention keeps values at its boundary",
+			retention: 10,
+			maxValues: 100,
+			values:    []value{{"eth", 9, 1}, {"eth", 10, 2}, {"eth", 20, 3}},
+			wantKept:  []value{{"eth", 10, 2}, {"eth", 20, 3}},
+			wantGone:  []value{{"eth", 9, 1}},
+		},
+		{
+			name:      "zero retention does not prune by age",
+			maxValues: 100,
+			values:    []value{{"eth", 1, 1}, {"eth", 1000000, 2}},
+			wantKept:  []value{{"eth", 1, 1}, {"eth", 1000000, 2}},
+		},
+		{
+			name:      "oldest blocks are pruned once the store is full",
+			maxValues: 2,
+			values:    []value{{"eth", 3, 1}, {"eth", 1, 2}, {"eth", 2, 3}},
+			wantKept:  []value{{"eth", 2, 3}, {"eth", 3, 1}},
+			wantGone:  []value{{"eth", 1, 2}},
+		},
+		{
+			name:      "other networks are not pruned by retention",
+			retention: 10,
+			maxValues: 100,
+			values:    []value{{"bsc", 1, 1}, {"eth", 1, 2}, {"eth", 20, 3}},
+			wantKept:  []value{{"bsc", 1, 1}, {"eth", 20, 3}},
+			wantGone:  []value{{"eth", 1, 2}},
+		},
+		{
+			name:      "hash with the separator is pruned",
+			retention: 10,
+			maxValues: 100,
+			values:    []value{{"eth", 1, 0x2f}, {"eth", 20, 1}},
+			wantKept:  []value{{"eth", 20, 1}},
+			wantGone:  []value{{"eth", 1, 0x2f}},
+		},
+	}
+
+	hashOf := func(v value) common.Hash {
+		return common.BytesToHash([]byte{v.hash, '/', v.hash})
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// every test value takes the same space, only its block differs in length
+			sample := storedValue{Block: 1000000}
+			valueSize := uint64(len(storeValueKey("eth", "tx", common.Hash{})) +
+				len(storeBlockKey("eth", 0, "tx", common.Hash{})) +
+				len(`{"block":1000000}`))
+
+			store, err := OpenStore(StoreOpts{
+				Path:            t.TempDir(),
+				MaxSize:         c.maxValues * valueSize,
+				RetentionBlocks: c.retention,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer store.Close()
+
+			for _, v := range c.values {
+				sample.Block = v.block
+				if err := store.put(v.network, "tx", hashOf(v), sample); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, v := range c.wantKept {
+				if _, ok, err := store.get(v.network, "tx", hashOf(v)); err != nil || !ok {
+					t.Errorf("value of %s block %d is pruned, err: %v", v.network, v.block, err)
+				}
+			}
+
+			for _, v := range c.wantGone {
+				if _, ok, err := store.get(v.network, "tx", hashOf(v)); err != nil || ok {
+					t.Errorf("value of %s block %d is kept, err: %v", v.network, v.block, err)
+				}
+			}
+
+			if store.size > store.maxSize {
+				t.Errorf("size %d exceeds max size %d", store.size, store.maxSize)
+			}
+		})
+	}
+}
+
+func TestStoreSizeAfterReopen(t *testing.T) {
+	path := t.TempDir()
+
+	store, err := OpenStore(StoreOpts{Path: path, MaxSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := uint64(1); i <= 5; i++ {
+		if err := store.put("eth", "tx", common.BigToHash(new(big.Int).SetUint64(i)), storedValue{Block: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	size := store.size
+
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = OpenStore(StoreOpts{Path: path, MaxSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if store.size != size {
+		t.Errorf("measured size = %d, want %d", store.size, size)
+	}
+
+	last, ok, err := store.LastBlock("eth")
+	if err != nil || !ok || last != 5 {
+		t.Errorf("last block = %d, %v, %v, want 5", last, ok, err)
+	}
+}
@@ -0,0 +1,166 @@
+package evm
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	"github.com/rarimo/evm-saver-svc/internal/services/policy"
+	"github.com/rarimo/saver-grpc-lib/broadcaster"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// BackfillReport sums up the deposit events found by the backfill.
+type BackfillReport struct {
+	Found     uint64
+	Submitted uint64
+	// Skipped are events already present on core
+	Skipped uint64
+	// Rejected are events the deposit policy does not allow to submit
+	Rejected uint64
+	Failed   uint64
+}
+
+func (r BackfillReport) Fields() logan.F {
+	return logan.F{
+		"found":     r.Found,
+		"submitted": r.Submitted,
+		"skipped":   r.Skipped,
+		"rejected":  r.Rejected,
+		"failed":    r.Failed,
+	}
+}
+
+// Backfill scans the closed block range for deposit events and broadcasts transfers core does not have
+// yet. It keeps no state and opens neither the storage nor the tx store, so it is safe to run next to a
+// live saver: events both of them broadcast are created on core only once.
+func Backfill(ctx context.Context, cfg config.Config, network *config.Ethereum, from, to uint64) (BackfillReport, error) {
+	log := cfg.Log().WithFields(logan.F{
+		"who":     "backfill",
+		"network": network.NetworkName,
+	})
+
+	var report BackfillReport
+
+	if from > to {
+		return report, errors.From(errors.New("range start is after its end"), logan.F{
+			"from": from,
+			"to":   to,
+		})
+	}
+
+	lastFinal, ok, err := network.Finality.LastFinal(ctx)
+	if err != nil {
+		return report, errors.Wrap(err, "failed to get last final block")
+	}
+
+	if !ok || to > lastFinal {
+		return report, errors.From(errors.New("range end is not final yet"), logan.F{
+			"to":         to,
+			"last_final": lastFinal,
+		})
+	}
+
+	topics := depositTopics(network.Listeners)
+	if len(topics) == 0 {
+		log.Warn("All deposit listeners are disabled, nothing to scan")
+		return report, nil
+	}
+
+	b := backfiller{
+		log:         log,
+		logs:        newLogsFetcher(network, log, topics),
+		msger:       rarimo.NewMessageMaker(cfg, network),
+		broadcaster: cfg.Broadcaster(),
+		checker:     rarimo.NewOperationChecker(cfg, network),
+		policy:      cfg.Policy(),
+		senders:     network.TxProvider,
+		report:      &report,
+	}
+
+	for from <= to {
+		logs, header, err := b.logs.fetch(ctx, from, to)
+		if err != nil {
+			return report, errors.Wrap(err, "failed to filter deposit events", logan.F{
+				"from": from,
+			})
+		}
+
+		sortLogs(logs)
+
+		for _, raw := range logs {
+			b.process(ctx, raw)
+		}
+
+		from = header.Number.Uint64() + 1
+	}
+
+	return report, nil
+}
+
+type backfiller struct {
+	log         *logan.Entry
+	logs        *logsFetcher
+	msger       *rarimo.MessageMaker
+	broadcaster broadcaster.Broadcaster
+	checker     *rarimo.OperationChecker
+	policy      *policy.Policy
+	senders     policy.SenderProvider
+	report      *BackfillReport
+}
+
+// process submits the event if it is missing on core. Failed events are reported and skipped, so the
+// backfill can be repeated for them.
+func (b *backfiller) process(ctx context.Context, raw types.Log) {
+	b.report.Found++
+	log := b.log.WithFields(logFields(raw))
+
+	submitted, err := b.checker.IsSubmitted(ctx, raw)
+	if err != nil {
+		b.report.Failed++
+		log.WithError(err).Error("failed to check event on core")
+		return
+	}
+
+	if submitted {
+		b.report.Skipped++
+		log.Debug("event is already present on core, skipping")
+		return
+	}
+
+	event, err := events.Decode(raw)
+	if err != nil {
+		b.report.Failed++
+		log.WithError(err).Error("failed to decode event")
+		return
+	}
+
+	rejection, err := b.policy.Check(ctx, event, b.senders)
+	if err != nil {
+		b.report.Failed++
+		log.WithError(err).Error("failed to check deposit policy")
+		return
+	}
+
+	if rejection != nil {
+		b.report.Rejected++
+		log.WithFields(logan.F{
+			"rule":    rejection.Rule,
+			"details": rejection.Details,
+		}).Warn("event is rejected by the deposit policy")
+		return
+	}
+
+	err = rarimo.MakeAndBroadcastMsg(ctx, b.msger, b.broadcaster, event)
+	if err != nil {
+		b.report.Failed++
+		log.WithError(err).Error("failed to make and broadcast msg")
+		return
+	}
+
+	b.report.Submitted++
+	log.Info("event is submitted")
+}
@@ -0,0 +1,107 @@
+package evm
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	initialBlocksPerRequest = 100
+
+	// a window is considered cheap for the provider and the range grows if both limits are not reached
+	growMaxLogs     = 1000
+	growMaxDuration = 2 * time.Second
+)
+
+// rangeErrors are lowercase parts of errors providers return when eth_getLogs range or result is too big.
+var rangeErrors = []string{
+	"query returned more than",
+	"block range",
+	"range too large",
+	"range is too",
+	"exceeds max results",
+	"response size",
+}
+
+// notRangeErrors are lowercase parts of quota, rate and timeout errors. They are not about the range and
+// are handled by the listener back off, shrinking the range would only make more requests.
+var notRangeErrors = []string{
+	"rate limit",
+	"429",
+	"too many requests",
+	"quota",
+	"daily request",
+	"request limit",
+	"deadline exceeded",
+	"timeout",
+	"timed out",
+}
+
+// blockRange is the amount of blocks requested in one eth_getLogs call. It doubles while responses are
+// small and fast, and halves when the provider rejects the request.
+type blockRange struct {
+	size uint64
+	min  uint64
+	max  uint64
+}
+
+func newBlockRange(min, max uint64) *blockRange {
+	r := &blockRange{size: initialBlocksPerRequest, min: min, max: max}
+	r.clamp()
+	return r
+}
+
+// last returns the last block (inclusive) of the window starting at from.
+func (r *blockRange) last(from uint64) uint64 {
+	return from + r.size - 1
+}
+
+// observe grows the range after a successful request if the response was small and fast enough.
+func (r *blockRange) observe(logs int, took time.Duration) bool {
+	if logs >= growMaxLogs || took >= growMaxDuration || r.size == r.max {
+		return false
+	}
+
+	r.size *= 2
+	r.clamp()
+	return true
+}
+
+// shrink halves the range, it returns false if the range is already minimal.
+func (r *blockRange) shrink() bool {
+	if r.size == r.min {
+		return false
+	}
+
+	r.size /= 2
+	r.clamp()
+	return true
+}
+
+func (r *blockRange) clamp() {
+	if r.size < r.min {
+		r.size = r.min
+	}
+
+	if r.size > r.max {
+		r.size = r.max
+	}
+}
+
+func isRangeError(err error) bool {
+	msg := strings.ToLower(err.Error())
+
+	for _, part := range notRangeErrors {
+		if strings.Contains(msg, part) {
+			return false
+		}
+	}
+
+	for _, part := range rangeErrors {
+		if strings.Contains(msg, part) {
+			return true
+		}
+	}
+
+	return false
+}
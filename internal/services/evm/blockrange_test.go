@@ -0,0 +1,125 @@
+package evm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func TestIsRangeError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"infura", errors.New("query returned more than 10000 results"), true},
+		{"alchemy", errors.New("Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"), true},
+		{"quicknode", errors.New("eth_getLogs is limited to a 10,000 range"), false},
+		{"ankr", errors.New("block range is too wide"), true},
+		{"chainstack", errors.New("range too large, max is 5000 blocks"), true},
+		{"bsc", errors.New("exceeds max results 20000, retry with the range 1-100"), true},
+		{"wrapped", errors.Wrap(errors.New("block range too big"), "failed to filter logs"), true},
+		{"deadline", errors.Wrap(context.DeadlineExceeded, "failed to filter logs"), false},
+		{"quota", errors.New("daily request limit exceeded, upgrade your plan"), false},
+		{"monthly quota", errors.New("monthly quota exceeded"), false},
+		{"rate", errors.New("429 Too Many Requests: rate limit exceeded"), false},
+		{"too many requests", errors.New("too many requests, slow down"), false},
+		{"timeout", errors.New("request timeout on the block range"), false},
+		{"unrelated", errors.New("connection refused"), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isRangeError(c.err); got != c.want {
+				t.Errorf("isRangeError(%q) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestBlockRange(t *testing.T) {
+	cases := []struct {
+		name     string
+		min, max uint64
+		steps    func(r *blockRange) bool
+		wantOK   bool
+		wantSize uint64
+	}{
+		{
+			name: "initial size is clamped to max",
+			min:  1, max: 50,
+			steps:    func(r *blockRange) bool { return true },
+			wantOK:   true,
+			wantSize: 50,
+		},
+		{
+			name: "initial size is clamped to min",
+			min:  500, max: 1000,
+			steps:    func(r *blockRange) bool { return true },
+			wantOK:   true,
+			wantSize: 500,
+		},
+		{
+			name: "small fast response grows",
+			min:  1, max: 1000,
+			steps:    func(r *blockRange) bool { return r.observe(10, time.Millisecond) },
+			wantOK:   true,
+			wantSize: 200,
+		},
+		{
+			name: "growth stops at max",
+			min:  1, max: 150,
+			steps:    func(r *blockRange) bool { return r.observe(10, time.Millisecond) && r.observe(10, time.Millisecond) },
+			wantOK:   false,
+			wantSize: 150,
+		},
+		{
+			name: "many logs do not grow",
+			min:  1, max: 1000,
+			steps:    func(r *blockRange) bool { return r.observe(growMaxLogs, time.Millisecond) },
+			wantOK:   false,
+			wantSize: 100,
+		},
+		{
+			name: "slow response does not grow",
+			min:  1, max: 1000,
+			steps:    func(r *blockRange) bool { return r.observe(10, growMaxDuration) },
+			wantOK:   false,
+			wantSize: 100,
+		},
+		{
+			name: "shrink halves",
+			min:  1, max: 1000,
+			steps:    func(r *blockRange) bool { return r.shrink() },
+			wantOK:   true,
+			wantSize: 50,
+		},
+		{
+			name: "shrink stops at min",
+			min:  80, max: 1000,
+			steps:    func(r *blockRange) bool { return r.shrink() && r.shrink() },
+			wantOK:   false,
+			wantSize: 80,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := newBlockRange(c.min, c.max)
+
+			if ok := c.steps(r); ok != c.wantOK {
+				t.Errorf("steps returned %v, want %v", ok, c.wantOK)
+			}
+
+			if r.size != c.wantSize {
+				t.Errorf("size = %d, want %d", r.size, c.wantSize)
+			}
+
+			if last := r.last(10); last != 10+c.wantSize-1 {
+				t.Errorf("last(10) = %d, want %d", last, 10+c.wantSize-1)
+			}
+		})
+	}
+}
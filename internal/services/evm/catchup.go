@@ -0,0 +1,155 @@
+package evm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// catchUpWindow is a block range fetched by a catch-up worker together with transfer messages of its
+// events made ahead of time.
+type catchUpWindow struct {
+	from uint64
+	to   uint64
+
+	logs   []types.Log
+	msgs   map[string]*oracletypes.MsgCreateTransferOp
+	header *types.Header
+	took   time.Duration
+	err    error
+	done   chan struct{}
+}
+
+// isBehind reports whether the backlog up to lastFinal is more than a single round of catch-up workers.
+func (s *depositsScanner) isBehind(lastFinal uint64) bool {
+	if s.workers < 2 || lastFinal < s.fromBlock {
+		return false
+	}
+
+	return lastFinal-s.fromBlock+1 > s.logs.blocks.size*uint64(s.workers)
+}
+
+// catchUp processes the backlog in rounds of windows fetched concurrently by catch-up workers while the
+// scanner is behind lastFinal. Windows are handled and committed strictly in block order, so the
+// checkpoint never passes a window that has not been processed.
+func (s *depositsScanner) catchUp(ctx context.Context, lastFinal uint64) error {
+	for s.isBehind(lastFinal) {
+		if err := s.catchUpRound(ctx); err != nil {
+			return err
+		}
+
+		changed, err := s.controlChanged()
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			return errInterrupted
+		}
+	}
+
+	return nil
+}
+
+func (s *depositsScanner) catchUpRound(ctx context.Context) error {
+	// workers of windows left after a failure are not needed anymore
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	windows := make([]*catchUpWindow, s.workers)
+	from := s.fromBlock
+
+	for i := range windows {
+		windows[i] = &catchUpWindow{
+			from: from,
+			to:   s.logs.blocks.last(from),
+			done: make(chan struct{}),
+		}
+
+		from = windows[i].to + 1
+
+		go s.prefetch(ctx, windows[i])
+	}
+
+	s.log.Infof("Catching up from %d to %d with %d workers", windows[0].from, windows[len(windows)-1].to, s.workers)
+
+	var (
+		maxLogs int
+		maxTook time.Duration
+	)
+
+	for _, w := range windows {
+		<-w.done
+
+		if w.err != nil {
+			if isRangeError(w.err) && s.logs.blocks.shrink() {
+				s.log.WithError(w.err).Warnf("Provider rejected the window, block range is decreased to %d", s.logs.blocks.size)
+				return nil
+			}
+
+			return errors.Wrap(w.err, "failed to filter deposit events", logan.F{
+				"from": w.from,
+				"to":   w.to,
+			})
+		}
+
+		s.log.Infof("Processing %d events from %d to %d", len(w.logs), w.from, w.to)
+
+		for _, raw := range w.logs {
+			if err := s.process(ctx, raw, w.msgs[eventKey(raw)]); err != nil {
+				return errors.Wrap(err, "failed to process event")
+			}
+		}
+
+		s.commitWindow(w.header)
+
+		if len(w.logs) > maxLogs {
+			maxLogs = len(w.logs)
+		}
+
+		if w.took > maxTook {
+			maxTook = w.took
+		}
+	}
+
+	if s.logs.blocks.observe(maxLogs, maxTook) {
+		s.log.Debugf("Block range is increased to %d", s.logs.blocks.size)
+	}
+
+	return nil
+}
+
+// prefetch fetches the window logs and makes transfer messages for them. It runs concurrently with the
+// scanner, so it must not touch the listener state.
+func (s *depositsScanner) prefetch(ctx context.Context, w *catchUpWindow) {
+	defer close(w.done)
+
+	w.logs, w.header, w.took, w.err = s.logs.fetchWindow(ctx, w.from, w.to)
+	if w.err != nil {
+		return
+	}
+
+	sortLogs(w.logs)
+	w.msgs = make(map[string]*oracletypes.MsgCreateTransferOp, len(w.logs))
+
+	for _, raw := range w.logs {
+		event, err := events.Decode(raw)
+		if err != nil {
+			continue
+		}
+
+		// events failed here are handled in the usual way, making the message once again
+		msg, err := s.msger.TransferMsg(ctx, event)
+		if err != nil {
+			s.log.WithError(err).WithFields(logFields(raw)).Debug("failed to make transfer msg ahead")
+			continue
+		}
+
+		w.msgs[eventKey(raw)] = msg
+	}
+}
@@ -0,0 +1,193 @@
+package evm
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// errInterrupted means the operator has changed the listener control, so the scanner has to get back
+// to the main loop to apply it.
+var errInterrupted = errors.New("listener control is changed")
+
+// applyControl loads the listener state set through the admin api and applies it. A requested rewind
+// is applied once and cleared. Events buffered while broadcasting was paused are submitted as soon as
+// it is resumed. It returns false if the listener is paused.
+func (s *depositsScanner) applyControl(ctx context.Context) (bool, error) {
+	control, err := s.controls.Get(s.network, s.name)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get listener control")
+	}
+
+	if control.RewindTo != nil {
+		rewindTo := *control.RewindTo
+
+		// skipping blocks forward would lose their deposits, the api does not accept such rewinds either
+		if rewindTo > s.fromBlock {
+			s.log.WithFields(logan.F{
+				"from_block": s.fromBlock,
+				"rewind_to":  rewindTo,
+			}).Warn("Ignoring rewind ahead of the listener")
+		} else if err := s.rewindTo(rewindTo); err != nil {
+			return false, errors.Wrap(err, "failed to rewind listener")
+		}
+
+		// another rewind could be requested meanwhile, it is kept to be applied next time
+		control, err = s.controls.Update(s.network, s.name, func(control *storage.ListenerControl) {
+			if control.RewindTo != nil && *control.RewindTo == rewindTo {
+				control.RewindTo = nil
+			}
+		})
+		if err != nil {
+			return false, errors.Wrap(err, "failed to clear applied rewind")
+		}
+	}
+
+	s.setControl(control)
+
+	if control.Paused {
+		return false, nil
+	}
+
+	if control.BroadcastPaused {
+		return true, nil
+	}
+
+	if err := s.drain(ctx); err != nil {
+		return false, errors.Wrap(err, "failed to submit buffered events")
+	}
+
+	return true, nil
+}
+
+// controlChanged reports whether the listener has to stop what it is doing to apply the new control.
+func (l *listener) controlChanged() (bool, error) {
+	control, err := l.controls.Get(l.network, l.name)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get listener control")
+	}
+
+	return control.Paused || control.RewindTo != nil || control.BroadcastPaused != l.control.BroadcastPaused, nil
+}
+
+func (l *listener) setControl(control storage.ListenerControl) {
+	if control.Paused != l.control.Paused {
+		if control.Paused {
+			l.log.Warn("Listener is paused by operator request")
+		} else {
+			l.log.Warn("Listener is resumed by operator request")
+		}
+	}
+
+	if control.BroadcastPaused != l.control.BroadcastPaused {
+		if control.BroadcastPaused {
+			l.log.Warn("Broadcasting is paused by operator request, deposits are buffered")
+		} else {
+			l.log.Warn("Broadcasting is resumed by operator request")
+		}
+	}
+
+	listenerPausedMetric.WithLabelValues(l.network, l.name).Set(boolGauge(control.Paused))
+	broadcastPausedMetric.WithLabelValues(l.network, l.name).Set(boolGauge(control.BroadcastPaused))
+
+	l.control = control
+}
+
+// rewindTo makes the listener continue from the block. Blocks after it are forgotten, so events there
+// are scanned again and the ones core already has are skipped.
+func (l *listener) rewindTo(block uint64) error {
+	l.log.WithFields(logan.F{
+		"from_block": l.fromBlock,
+		"rewind_to":  block,
+	}).Warn("Listener is rewound by operator request")
+
+	if err := l.history.Rewind(l.network, l.name, block-1); err != nil {
+		return errors.Wrap(err, "failed to rewind history")
+	}
+
+	l.handled = nil
+	l.attempts = make(map[string]uint64)
+	l.reincluded = make(map[string]struct{})
+	l.commit(block - 1)
+
+	listenerRewindsMetric.WithLabelValues(l.network, l.name).Inc()
+	return nil
+}
+
+// hold buffers the event instead of submitting it while broadcasting is paused.
+func (l *listener) hold(raw types.Log) error {
+	if err := l.buffer.Put(l.network, l.name, raw); err != nil {
+		return err
+	}
+
+	bufferedEventsMetric.WithLabelValues(l.network, l.name).Inc()
+	l.log.WithFields(logFields(raw)).Info("broadcasting is paused, event is buffered")
+	return nil
+}
+
+// drain submits buffered events in (block, log index) order. An event leaves the buffer once it is
+// submitted, rejected by the policy or set aside, a failing one stops draining until the next iteration.
+func (s *depositsScanner) drain(ctx context.Context) error {
+	buffered, err := s.buffer.List(s.network, s.name)
+	if err != nil {
+		return errors.Wrap(err, "failed to list buffered events")
+	}
+
+	if len(buffered) == 0 {
+		return nil
+	}
+
+	s.log.Infof("Submitting %d events buffered while broadcasting was paused", len(buffered))
+
+	for _, raw := range buffered {
+		if err := s.release(ctx, raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *depositsScanner) release(ctx context.Context, raw types.Log) error {
+	key := eventKey(raw)
+
+	event, err := events.Decode(raw)
+	if err != nil {
+		// the layout is checked before buffering, so retrying will not help
+		if err := s.setAside(raw, errors.Wrap(err, "failed to decode event"), 1); err != nil {
+			return errors.Wrap(err, "failed to set event aside")
+		}
+
+		return s.unbuffer(raw)
+	}
+
+	if err := s.deliver(ctx, s.msger, event, nil); err != nil {
+		if err := s.failed(raw, err); err != nil {
+			return err
+		}
+	}
+
+	delete(s.attempts, key)
+	return s.unbuffer(raw)
+}
+
+func (l *listener) unbuffer(raw types.Log) error {
+	if err := l.buffer.Delete(l.network, l.name, raw); err != nil {
+		return errors.Wrap(err, "failed to delete released event from buffer")
+	}
+
+	bufferedEventsMetric.WithLabelValues(l.network, l.name).Dec()
+	return nil
+}
+
+func boolGauge(value bool) float64 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,140 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type logsClient interface {
+	Pinned(ctx context.Context, block uint64, request func(ctx context.Context, client *ethclient.Client) error) error
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// logsFetcher requests deposit logs of the bridge contracts in windows of the adaptive block range.
+// Logs of a contract outside of its active range are dropped.
+type logsFetcher struct {
+	log       *logan.Entry
+	blocks    *blockRange
+	client    logsClient
+	contracts config.Contracts
+	topics    []common.Hash
+}
+
+func newLogsFetcher(network *config.Ethereum, log *logan.Entry, topics []common.Hash) *logsFetcher {
+	return &logsFetcher{
+		log:       log,
+		blocks:    newBlockRange(network.MinBlocksPerRequest, network.MaxBlocksPerRequest),
+		client:    network.RPCClient,
+		contracts: network.Contracts,
+		topics:    topics,
+	}
+}
+
+// fetch requests deposit logs from the from block up to the last one, limiting the window by the block
+// range. If the provider rejects the window as too big, it is halved and requested again. It returns
+// the header of the last block of the window actually fetched. The header is requested before logs, so
+// a reorg happening in between leaves an outdated hash that is detected before the next window.
+func (f *logsFetcher) fetch(ctx context.Context, from, last uint64) ([]types.Log, *types.Header, error) {
+	for {
+		lastBlock := last
+		if windowLast := f.blocks.last(from); windowLast < lastBlock {
+			lastBlock = windowLast
+		}
+
+		logs, header, took, err := f.fetchWindow(ctx, from, lastBlock)
+		if err == nil {
+			// growing the range makes sense only if the window was not limited by the last block
+			if lastBlock < last && f.blocks.observe(len(logs), took) {
+				f.log.Debugf("Block range is increased to %d", f.blocks.size)
+			}
+
+			return logs, header, nil
+		}
+
+		if !isRangeError(err) || !f.blocks.shrink() {
+			return nil, nil, err
+		}
+
+		f.log.WithError(err).Warnf("Provider rejected the window, block range is decreased to %d", f.blocks.size)
+	}
+}
+
+// fetchWindow requests deposit logs of exactly the given window and the header of its last block. It
+// does not touch the block range, so windows can be fetched concurrently.
+func (f *logsFetcher) fetchWindow(ctx context.Context, from, to uint64) ([]types.Log, *types.Header, time.Duration, error) {
+	f.log.Infof("Starting subscription from %d to %d", from, to)
+
+	query := f.query()
+	query.FromBlock = new(big.Int).SetUint64(from)
+	query.ToBlock = new(big.Int).SetUint64(to)
+
+	var (
+		header *types.Header
+		logs   []types.Log
+		took   time.Duration
+	)
+
+	// a node behind the window returns no logs for blocks it does not have instead of an error, and the
+	// window would be committed without them
+	err := f.client.Pinned(ctx, to, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+
+		header, err = client.HeaderByNumber(ctx, query.ToBlock)
+		if err != nil {
+			return errors.Wrap(err, "failed to get window header")
+		}
+
+		start := time.Now()
+
+		logs, err = client.FilterLogs(ctx, query)
+		took = time.Since(start)
+		return err
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return f.filter(logs), header, took, nil
+}
+
+// filter drops logs not emitted by the contract active in their blocks.
+func (f *logsFetcher) filter(logs []types.Log) []types.Log {
+	accepted := logs[:0]
+
+	for _, raw := range logs {
+		if !f.accepts(raw) {
+			continue
+		}
+
+		accepted = append(accepted, raw)
+	}
+
+	return accepted
+}
+
+func (f *logsFetcher) accepts(raw types.Log) bool {
+	if f.contracts.Accepts(raw) {
+		return true
+	}
+
+	f.log.WithFields(logFields(raw)).WithField("contract", raw.Address).
+		Debug("dropping log of the contract inactive in its block")
+	return false
+}
+
+func (f *logsFetcher) query() ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		Addresses: f.contracts.Addresses(),
+		Topics:    [][]common.Hash{f.topics},
+	}
+}
@@ -0,0 +1,186 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/saver-grpc-lib/metrics"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const liveChanSize = 100
+
+// live receives deposit logs and new heads through websocket subscriptions. Logs are kept pending until
+// their block becomes final, logs removed by a reorg are dropped before that. Blocks
+// produced before the subscription started are not delivered by it, so they are still polled. Any
+// subscription or processing error, as well as a reorg of processed blocks, makes the scanner go back
+// to polling from the last checkpoint.
+func (s *depositsScanner) live(ctx context.Context) error {
+	logs := make(chan types.Log, liveChanSize)
+
+	logsSub, err := s.logs.client.SubscribeFilterLogs(ctx, s.logs.query(), logs)
+	if err != nil {
+		metrics.WebsocketMetric.Set(metrics.WebsocketDisconnected)
+		return errors.Wrap(err, "failed to subscribe to deposit logs")
+	}
+	defer logsSub.Unsubscribe()
+
+	heads := make(chan *types.Header, liveChanSize)
+
+	headsSub, err := s.logs.client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		metrics.WebsocketMetric.Set(metrics.WebsocketDisconnected)
+		return errors.Wrap(err, "failed to subscribe to new heads")
+	}
+	defer headsSub.Unsubscribe()
+
+	subscribedAt, err := s.blockHandler.BlockNumber(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get recent block")
+	}
+
+	metrics.WebsocketMetric.Set(metrics.WebsocketAvailable)
+	defer metrics.WebsocketMetric.Set(metrics.WebsocketDisconnected)
+
+	s.log.Infof("Switched to websocket subscription at block %d", subscribedAt)
+
+	pending := make(pendingLogs)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-logsSub.Err():
+			return errors.Wrap(err, "deposit logs subscription dropped")
+		case err := <-headsSub.Err():
+			return errors.Wrap(err, "new heads subscription dropped")
+		case raw := <-logs:
+			s.receive(pending, raw)
+		case <-heads:
+			err := s.confirm(ctx, pending, subscribedAt)
+			if errors.Cause(err) == errReorged {
+				s.log.Info("Switched back to polling to rescan reorganized blocks")
+				return nil
+			}
+
+			if errors.Cause(err) == errInterrupted {
+				s.log.Info("Switched back to polling to apply listener control")
+				return nil
+			}
+
+			if err != nil {
+				return errors.Wrap(err, "failed to confirm pending logs")
+			}
+		}
+	}
+}
+
+func (s *depositsScanner) receive(pending pendingLogs, raw types.Log) {
+	if !s.logs.accepts(raw) {
+		return
+	}
+
+	if !raw.Removed {
+		s.log.WithFields(logFields(raw)).Debug("got pending event")
+		pending.add(raw)
+		return
+	}
+
+	if raw.BlockNumber < s.fromBlock {
+		s.log.WithFields(logFields(raw)).Error("processed event was removed by a reorg of a final block")
+		return
+	}
+
+	s.log.WithFields(logFields(raw)).Info("pending event was removed by a reorg")
+	pending.remove(raw)
+}
+
+// confirm processes pending logs that became final with the new head.
+func (s *depositsScanner) confirm(ctx context.Context, pending pendingLogs, subscribedAt uint64) error {
+	changed, err := s.controlChanged()
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		return errInterrupted
+	}
+
+	if s.fromBlock <= subscribedAt {
+		_, err := s.poll(ctx)
+		return err
+	}
+
+	lastFinal, ok, err := s.finality.LastFinal(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get last final block")
+	}
+
+	if !ok || lastFinal < s.fromBlock {
+		return nil
+	}
+
+	reorged, err := s.checkReorg(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to check processed blocks for reorg")
+	}
+
+	if reorged {
+		return errReorged
+	}
+
+	header, err := s.blockHandler.HeaderByNumber(ctx, new(big.Int).SetUint64(lastFinal))
+	if err != nil {
+		return errors.Wrap(err, "failed to get confirmed header")
+	}
+
+	for _, raw := range pending.take(lastFinal) {
+		if raw.BlockNumber < s.fromBlock {
+			continue
+		}
+
+		if err := s.process(ctx, raw, nil); err != nil {
+			return errors.Wrap(err, "failed to process event")
+		}
+	}
+
+	s.commitWindow(header)
+	return nil
+}
+
+// pendingLogs are logs waiting for confirmations by block number.
+type pendingLogs map[uint64][]types.Log
+
+func (p pendingLogs) add(raw types.Log) {
+	p[raw.BlockNumber] = append(p[raw.BlockNumber], raw)
+}
+
+func (p pendingLogs) remove(raw types.Log) {
+	kept := p[raw.BlockNumber][:0]
+
+	for _, log := range p[raw.BlockNumber] {
+		if log.BlockHash != raw.BlockHash || log.TxHash != raw.TxHash || log.Index != raw.Index {
+			kept = append(kept, log)
+		}
+	}
+
+	p[raw.BlockNumber] = kept
+}
+
+// take removes and returns logs up to the lastBlock (inclusive) sorted by (block, log index).
+func (p pendingLogs) take(lastBlock uint64) []types.Log {
+	var result []types.Log
+
+	for block, logs := range p {
+		if block > lastBlock {
+			continue
+		}
+
+		result = append(result, logs...)
+		delete(p, block)
+	}
+
+	sortLogs(result)
+	return result
+}
@@ -2,32 +2,455 @@ package evm
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	"github.com/rarimo/evm-saver-svc/internal/services/finality"
+	"github.com/rarimo/evm-saver-svc/internal/services/policy"
+	"github.com/rarimo/evm-saver-svc/internal/services/progress"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
 	"github.com/rarimo/saver-grpc-lib/broadcaster"
 	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-const MaxBlocksPerRequest = 100
-
 type blockHandler interface {
 	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 }
 
 type listener struct {
+	name         string
+	network      string
 	log          *logan.Entry
 	blockHandler blockHandler
 	broadcaster  broadcaster.Broadcaster
+	checkpoints  *storage.Checkpoints
+	quarantine   *storage.Quarantine
+	history      *storage.History
+	historySize  int
 	fromBlock    uint64
-	blockWindow  uint64
+	finality     *finality.Source
+
+	// handled is the last event successfully processed at or after fromBlock. It lets the listener skip
+	// already broadcast events when the window is retried starting from the block of the failed event.
+	handled     *types.Log
+	attempts    map[string]uint64
+	maxAttempts uint64
+
+	// reincluded are submitted events that a reorg moved to other blocks keeping their log index, so
+	// they must not be submitted again when the listener rescans.
+	reincluded map[string]struct{}
+
+	operations *rarimo.OperationChecker
+	skipped    uint64
+
+	policy     *policy.Policy
+	senders    policy.SenderProvider
+	rejections *storage.Rejections
+
+	progress *progress.Tracker
+
+	controls *storage.Controls
+	control  storage.ListenerControl
+	buffer   *storage.Buffer
 }
 
-func newListener(cfg config.Config) *listener {
-	return &listener{
-		log:          cfg.Log(),
-		blockHandler: cfg.Ethereum().RPCClient,
+func newListener(cfg config.Config, network *config.Ethereum, log *logan.Entry, name string, legacyNames ...string) *listener {
+	l := &listener{
+		name:         name,
+		network:      network.NetworkName,
+		log:          log,
+		blockHandler: network.RPCClient,
 		broadcaster:  cfg.Broadcaster(),
-		fromBlock:    cfg.Ethereum().StartFromBlock,
-		blockWindow:  cfg.Ethereum().BlockWindow,
+		checkpoints:  cfg.Storage().Checkpoints(),
+		quarantine:   cfg.Storage().Quarantine(),
+		history:      cfg.Storage().History(),
+		historySize:  int(network.ReorgHistory),
+		fromBlock:    network.StartFromBlock,
+		finality:     network.Finality,
+		attempts:     make(map[string]uint64),
+		maxAttempts:  network.MaxEventAttempts,
+		reincluded:   make(map[string]struct{}),
+		operations:   rarimo.NewOperationChecker(cfg, network),
+		policy:       cfg.Policy(),
+		senders:      network.TxProvider,
+		rejections:   cfg.Storage().Rejections(),
+		controls:     cfg.Storage().Controls(),
+		buffer:       cfg.Storage().Buffer(),
+	}
+
+	if err := l.restore(network, legacyNames); err != nil {
+		panic(errors.Wrap(err, "failed to restore listener checkpoint"))
+	}
+
+	l.progress = cfg.Progress().Track(l.network, l.name, l.fromBlock)
+
+	buffered, err := l.buffer.Count(l.network, l.name)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to count buffered events"))
+	}
+
+	bufferedEventsMetric.WithLabelValues(l.network, l.name).Set(float64(buffered))
+
+	log.Infof("Listener will start from block %d", l.fromBlock)
+	return l
+}
+
+// restore loads the saved checkpoint, which takes precedence over the configured start block unless
+// the start block is forced. A forced start block overwrites the checkpoint, so the listener does not
+// jump back to the old one if it is restarted before finishing the first window. If the listener has
+// no checkpoint yet, it resumes from the least one of the listeners it replaces, and only if there are
+// none the start block is resolved.
+func (l *listener) restore(network *config.Ethereum, legacyNames []string) error {
+	if network.ForceStartFromBlock {
+		l.log.Warnf("Forced to start from block %d, saved checkpoint is dropped", l.fromBlock)
+
+		if l.fromBlock == 0 {
+			return l.checkpoints.Delete(l.network, l.name)
+		}
+
+		return l.checkpoints.Set(l.network, l.name, l.fromBlock-1)
+	}
+
+	lastBlock, ok, err := l.checkpoints.Get(l.network, l.name)
+	if err != nil {
+		return errors.Wrap(err, "failed to get checkpoint")
+	}
+
+	if ok {
+		l.fromBlock = lastBlock + 1
+		return nil
+	}
+
+	restored := false
+
+	for _, name := range legacyNames {
+		legacyBlock, ok, err := l.checkpoints.Get(l.network, name)
+		if err != nil {
+			return errors.Wrap(err, "failed to get legacy checkpoint", logan.F{
+				"legacy_listener": name,
+			})
+		}
+
+		if ok && (!restored || legacyBlock+1 < l.fromBlock) {
+			l.fromBlock = legacyBlock + 1
+			restored = true
+		}
+	}
+
+	if restored {
+		return nil
+	}
+
+	l.fromBlock, err = network.ResolveStartBlock(context.TODO(), l.log)
+	return errors.Wrap(err, "failed to resolve start block")
+}
+
+// handle broadcasts the transfer message for the event unless the deposit policy rejects it, or buffers
+// the event if broadcasting is paused. If the listener control has changed, it returns errInterrupted
+// leaving the event to be handled once the control is applied. If it fails, the listener stays at the
+// event block, so the event is retried on the next iteration together with the rest of the window. An
+// event that has failed maxAttempts times in a row, or can not be handled at all, is quarantined, so it
+// does not block the following ones.
+func (l *listener) handle(ctx context.Context, msger *rarimo.MessageMaker, event events.Event, msg *oracletypes.MsgCreateTransferOp) error {
+	raw := event.Raw()
+	if l.isHandled(raw) {
+		l.log.WithFields(logFields(raw)).Debug("event is already handled, skipping")
+		return nil
+	}
+
+	key := eventKey(raw)
+
+	if _, ok := l.reincluded[key]; ok {
+		l.log.WithFields(logFields(raw)).Debug("event is already submitted before the reorg, skipping")
+		delete(l.reincluded, key)
+		l.handled = &raw
+		return nil
+	}
+
+	// the control is checked before every event, so a pause applies to the rest of the window at once
+	changed, err := l.controlChanged()
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		if raw.BlockNumber > l.fromBlock {
+			l.commit(raw.BlockNumber - 1)
+		}
+
+		return errInterrupted
+	}
+
+	if l.control.BroadcastPaused {
+		if err := l.hold(raw); err != nil {
+			return errors.Wrap(err, "failed to buffer event")
+		}
+
+		l.handled = &raw
+		return nil
+	}
+
+	if err := l.deliver(ctx, msger, event, msg); err != nil {
+		if err := l.failed(raw, err); err != nil {
+			if raw.BlockNumber > l.fromBlock {
+				l.commit(raw.BlockNumber - 1)
+			}
+
+			return err
+		}
+	}
+
+	delete(l.attempts, key)
+	l.handled = &raw
+	return nil
+}
+
+// failed counts the failed delivery of the event and quarantines the event once it has failed
+// maxAttempts times in a row or can not be handled at all. The error to retry the event with is
+// returned, nil if the event is quarantined.
+func (l *listener) failed(raw types.Log, err error) error {
+	key := eventKey(raw)
+
+	// outages of core, the broadcaster or the rpc are not the event fault, so they are retried forever
+	if !isUnavailable(err) {
+		l.attempts[key]++
+	}
+
+	if l.attempts[key] < l.maxAttempts && !isPoison(err) {
+		return errors.Wrap(err, "failed to make and broadcast msg", logan.F{
+			"attempt": l.attempts[key],
+		})
+	}
+
+	if err := l.setAside(raw, err, l.attempts[key]); err != nil {
+		return errors.Wrap(err, "failed to set event aside")
+	}
+
+	return nil
+}
+
+// deliver submits the event unless the deposit policy rejects it.
+func (l *listener) deliver(ctx context.Context, msger *rarimo.MessageMaker, event events.Event, msg *oracletypes.MsgCreateTransferOp) error {
+	rejected, err := l.filter(ctx, event)
+	if err != nil || rejected {
+		return err
+	}
+
+	if err := l.submit(ctx, msger, event, msg); err != nil {
+		return err
+	}
+
+	l.remember(event.Raw())
+	return nil
+}
+
+// filter applies the deposit policy to the event and records it if it is rejected.
+func (l *listener) filter(ctx context.Context, event events.Event) (bool, error) {
+	rejection, err := l.policy.Check(ctx, event, l.senders)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check deposit policy")
+	}
+
+	if rejection == nil {
+		return false, nil
+	}
+
+	raw := event.Raw()
+
+	err = l.rejections.Put(storage.RejectedEvent{
+		Network:    l.network,
+		Listener:   l.name,
+		Rule:       rejection.Rule,
+		Details:    rejection.Details,
+		Log:        raw,
+		RejectedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to record rejected event")
+	}
+
+	l.log.WithFields(logFields(raw)).WithFields(logan.F{
+		"rule":    rejection.Rule,
+		"details": rejection.Details,
+	}).Warn("event is rejected by the deposit policy")
+
+	eventsRejectedMetric.WithLabelValues(l.network, l.name, rejection.Rule).Inc()
+	return true, nil
+}
+
+// submit broadcasts the transfer message of the event, made ahead of time if msg is not nil. Events core
+// already has are skipped.
+func (l *listener) submit(ctx context.Context, msger *rarimo.MessageMaker, event events.Event, msg *oracletypes.MsgCreateTransferOp) error {
+	raw := event.Raw()
+
+	submitted, err := l.operations.IsSubmitted(ctx, raw)
+	if err != nil {
+		// not a reason to stall, core rejects the transfer anyway if it exists
+		l.log.WithError(err).WithFields(logFields(raw)).Warn("failed to check event on core")
+	}
+
+	if submitted {
+		l.skip(raw, skipReasonOnCore)
+		return nil
+	}
+
+	if msg != nil {
+		err = l.broadcaster.BroadcastTx(ctx, msg)
+	} else {
+		err = rarimo.MakeAndBroadcastMsg(ctx, msger, l.broadcaster, event)
+	}
+
+	return err
+}
+
+func (l *listener) skip(raw types.Log, reason string) {
+	l.skipped++
+	transfersSkippedMetric.WithLabelValues(l.network, l.name, reason).Inc()
+
+	l.log.WithFields(logFields(raw)).WithFields(logan.F{
+		"reason":        reason,
+		"skipped_total": l.skipped,
+	}).Info("transfer already exists on core, skipping")
+}
+
+func (l *listener) isHandled(raw types.Log) bool {
+	if l.handled == nil {
+		return false
+	}
+
+	if raw.BlockNumber != l.handled.BlockNumber {
+		return raw.BlockNumber < l.handled.BlockNumber
+	}
+
+	return raw.Index <= l.handled.Index
+}
+
+func (l *listener) setAside(raw types.Log, cause error, attempts uint64) error {
+	err := l.quarantine.Put(storage.QuarantinedEvent{
+		Network:       l.network,
+		Listener:      l.name,
+		Error:         cause.Error(),
+		Attempts:      attempts,
+		Log:           raw,
+		QuarantinedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to quarantine event")
+	}
+
+	l.log.WithError(cause).WithFields(logFields(raw)).WithFields(logan.F{
+		"attempts": attempts,
+	}).Error("event is quarantined for operator review")
+
+	eventsQuarantinedMetric.WithLabelValues(l.network, l.name).Inc()
+	return nil
+}
+
+// isPoison reports whether the event can not be handled until something changes on core, so there is
+// no point in retrying it.
+func isPoison(err error) bool {
+	return errors.Cause(err) == rarimo.ErrDestinationItemNotFound
+}
+
+// isUnavailable reports whether the error comes from core, the broadcaster or the rpc being unavailable
+// rather than from the event itself.
+func isUnavailable(err error) bool {
+	cause := errors.Cause(err)
+
+	switch cause {
+	case context.Canceled, context.DeadlineExceeded, io.EOF, io.ErrUnexpectedEOF:
+		return true
+	}
+
+	// the rpc pool fails requests with url errors once all endpoints fail
+	if _, ok := cause.(net.Error); ok {
+		return true
+	}
+
+	if httpErr, ok := cause.(rpc.HTTPError); ok {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	if s, ok := status.FromError(cause); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.ResourceExhausted, codes.Aborted:
+			return true
+		}
+	}
+
+	return false
+}
+
+// commit marks every block up to lastBlock (inclusive) as processed.
+func (l *listener) commit(lastBlock uint64) {
+	// https://ethereum.stackexchange.com/questions/8199/are-both-the-eth-newfilter-from-to-fields-inclusive
+	// End in FilterLogs is inclusive
+	l.fromBlock = lastBlock + 1
+	l.progress.Moved(l.fromBlock)
+
+	if err := l.checkpoints.Set(l.network, l.name, lastBlock); err != nil {
+		l.log.WithError(err).Errorf("failed to save checkpoint at block %d", lastBlock)
+	}
+}
+
+// commitWindow marks every block up to the header one as processed and remembers the header hash to
+// detect reorgs of the processed blocks later.
+func (l *listener) commitWindow(header *types.Header) {
+	l.commit(header.Number.Uint64())
+	l.progress.Processed(header.Time)
+
+	err := l.history.PutBlock(l.network, l.name, storage.BlockHash{
+		Number: header.Number.Uint64(),
+		Hash:   header.Hash(),
+	})
+	if err != nil {
+		l.log.WithError(err).Errorf("failed to remember block %d", header.Number.Uint64())
+		return
+	}
+
+	if err := l.history.Prune(l.network, l.name, l.historySize); err != nil {
+		l.log.WithError(err).Error("failed to prune blocks history")
+	}
+}
+
+// remember saves the submitted event, so it can be checked if a reorg rolls its block back.
+func (l *listener) remember(raw types.Log) {
+	err := l.history.PutTransfer(l.network, l.name, storage.SubmittedTransfer{
+		Block:     raw.BlockNumber,
+		BlockHash: raw.BlockHash,
+		TxHash:    raw.TxHash,
+		LogIndex:  raw.Index,
+	})
+	if err != nil {
+		l.log.WithError(err).WithFields(logFields(raw)).Error("failed to remember submitted transfer")
+	}
+}
+
+func eventKey(raw types.Log) string {
+	return fmt.Sprintf("%s-%d", raw.TxHash.String(), raw.Index)
+}
+
+func logFields(raw types.Log) logan.F {
+	return logan.F{
+		"block":     raw.BlockNumber,
+		"tx_hash":   raw.TxHash,
+		"tx_index":  raw.TxIndex,
+		"log_index": raw.Index,
 	}
 }
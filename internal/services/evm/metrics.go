@@ -0,0 +1,61 @@
+package evm
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// skipReasonOnCore is used when the transfer is found on core before broadcasting
+const skipReasonOnCore = "on_core"
+
+var (
+	eventsQuarantinedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_events_quarantined",
+		Help: "Number of deposit events moved to the quarantine",
+	}, []string{"network", "listener"})
+
+	eventsRejectedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_events_rejected",
+		Help: "Number of deposit events rejected by the deposit policy",
+	}, []string{"network", "listener", "rule"})
+
+	transfersSkippedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_transfers_skipped",
+		Help: "Number of deposit events not broadcast because core already has their transfers",
+	}, []string{"network", "listener", "reason"})
+
+	reorgsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_reorgs",
+		Help: "Number of chain reorganizations detected in processed blocks",
+	}, []string{"network", "listener"})
+
+	reorgDepthMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "evm_reorg_depth",
+		Help: "Number of processed blocks rolled back by the last detected reorganization",
+	}, []string{"network", "listener"})
+
+	orphanedTransfersMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_orphaned_transfers",
+		Help: "Number of submitted transfers whose deposit events were dropped by a reorganization",
+	}, []string{"network", "listener"})
+
+	listenerPausedMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "evm_listener_paused",
+		Help: "Whether the listener is paused through the admin api",
+	}, []string{"network", "listener"})
+
+	broadcastPausedMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "evm_broadcast_paused",
+		Help: "Whether broadcasting of the listener is paused through the admin api",
+	}, []string{"network", "listener"})
+
+	bufferedEventsMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "evm_buffered_events",
+		Help: "Number of deposit events buffered while broadcasting is paused",
+	}, []string{"network", "listener"})
+
+	listenerRewindsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_listener_rewinds",
+		Help: "Number of rewinds requested through the admin api",
+	}, []string{"network", "listener"})
+)
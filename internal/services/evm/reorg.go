@@ -0,0 +1,158 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// errReorged is returned when processing is interrupted by a rewind after a reorg.
+var errReorged = errors.New("processed blocks are reorganized")
+
+// checkReorg makes sure the last processed block, the parent of the next window, is still canonical.
+// Otherwise, it finds the latest remembered block that is still canonical and rewinds the listener to
+// it, so the rolled back blocks are rescanned. It returns true if the listener has been rewound.
+func (l *listener) checkReorg(ctx context.Context) (bool, error) {
+	blocks, err := l.history.Blocks(l.network, l.name)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get processed blocks")
+	}
+
+	if len(blocks) == 0 {
+		return false, nil
+	}
+
+	last := blocks[len(blocks)-1]
+
+	canonical, err := l.isCanonical(ctx, last)
+	if err != nil || canonical {
+		return false, err
+	}
+
+	var forkPoint uint64
+	found := false
+
+	for i := len(blocks) - 2; i >= 0; i-- {
+		canonical, err := l.isCanonical(ctx, blocks[i])
+		if err != nil {
+			return false, err
+		}
+
+		if canonical {
+			forkPoint, found = blocks[i].Number, true
+			break
+		}
+	}
+
+	if !found {
+		// nothing better is known, blocks before the oldest remembered window are considered final
+		forkPoint = blocks[0].Number - 1
+
+		l.log.WithFields(logan.F{
+			"oldest_remembered_block": blocks[0].Number,
+		}).Error("reorg is deeper than remembered history, processed blocks before it may be affected")
+	}
+
+	if err := l.rewind(ctx, forkPoint); err != nil {
+		return false, errors.Wrap(err, "failed to rewind", logan.F{
+			"fork_point": forkPoint,
+		})
+	}
+
+	depth := last.Number - forkPoint
+
+	l.log.WithFields(logan.F{
+		"fork_point": forkPoint,
+		"depth":      depth,
+	}).Warn("reorg detected, rescanning rolled back blocks")
+
+	reorgsMetric.WithLabelValues(l.network, l.name).Inc()
+	reorgDepthMetric.WithLabelValues(l.network, l.name).Set(float64(depth))
+	return true, nil
+}
+
+func (l *listener) isCanonical(ctx context.Context, block storage.BlockHash) (bool, error) {
+	header, err := l.blockHandler.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Number))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get header", logan.F{
+			"block": block.Number,
+		})
+	}
+
+	return header.Hash() == block.Hash, nil
+}
+
+// rewind moves the listener back to the block after forkPoint. Transfers submitted from the rolled back
+// blocks are checked against their receipts: the ones whose deposit events are gone are reported as
+// orphaned, the ones re-included with the same log index are not submitted again.
+func (l *listener) rewind(ctx context.Context, forkPoint uint64) error {
+	transfers, err := l.history.Transfers(l.network, l.name, forkPoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to get submitted transfers")
+	}
+
+	var reincluded []storage.SubmittedTransfer
+
+	for _, transfer := range transfers {
+		receipt, err := l.blockHandler.TransactionReceipt(ctx, transfer.TxHash)
+		if err != nil && err != ethereum.NotFound {
+			return errors.Wrap(err, "failed to get receipt", logan.F{
+				"tx_hash": transfer.TxHash,
+			})
+		}
+
+		if moved, ok := findTransfer(receipt, transfer); ok {
+			reincluded = append(reincluded, moved)
+			continue
+		}
+
+		l.log.WithFields(logan.F{
+			"block":     transfer.Block,
+			"tx_hash":   transfer.TxHash,
+			"log_index": transfer.LogIndex,
+		}).Error("submitted transfer is orphaned by reorg")
+
+		orphanedTransfersMetric.WithLabelValues(l.network, l.name).Inc()
+	}
+
+	if err := l.history.Rewind(l.network, l.name, forkPoint); err != nil {
+		return errors.Wrap(err, "failed to rewind history")
+	}
+
+	for _, transfer := range reincluded {
+		if err := l.history.PutTransfer(l.network, l.name, transfer); err != nil {
+			return errors.Wrap(err, "failed to put reincluded transfer")
+		}
+
+		l.reincluded[eventKey(types.Log{TxHash: transfer.TxHash, Index: transfer.LogIndex})] = struct{}{}
+	}
+
+	if l.handled != nil && l.handled.BlockNumber > forkPoint {
+		l.handled = nil
+	}
+
+	l.commit(forkPoint)
+	return nil
+}
+
+// findTransfer looks for the submitted deposit event in the canonical receipt of its transaction.
+func findTransfer(receipt *types.Receipt, transfer storage.SubmittedTransfer) (storage.SubmittedTransfer, bool) {
+	if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful {
+		return storage.SubmittedTransfer{}, false
+	}
+
+	for _, log := range receipt.Logs {
+		if log.Index == transfer.LogIndex {
+			transfer.Block = receipt.BlockNumber.Uint64()
+			transfer.BlockHash = receipt.BlockHash
+			return transfer, true
+		}
+	}
+
+	return storage.SubmittedTransfer{}, false
+}
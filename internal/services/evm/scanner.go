@@ -0,0 +1,217 @@
+package evm
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
+	"github.com/rarimo/saver-grpc-lib/metrics"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+)
+
+// legacyListeners are names of per token type listeners the scanner has replaced. Their checkpoints
+// are used to resume when the scanner has not saved its own one yet.
+var legacyListeners = []string{"inative_listener", "ierc20_listener", "ierc721_listener", "ierc1155_listener"}
+
+// DepositsScannerName is the name the scanner keeps its checkpoint and state under.
+const DepositsScannerName = "deposits_scanner"
+
+// RunDepositsScanner fetches deposit events of all enabled token types with a single eth_getLogs call
+// per window and processes them in (block, log index) order. In websocket mode, once the scanner has
+// caught up with the chain, it switches to the live subscription and gets back to polling if it drops.
+// Each network has its own scanner.
+func RunDepositsScanner(ctx context.Context, cfg config.Config, network *config.Ethereum) {
+	const runnerName = DepositsScannerName
+
+	log := cfg.Log().WithFields(logan.F{
+		"who":     runnerName,
+		"network": network.NetworkName,
+	})
+
+	topics := depositTopics(network.Listeners)
+	if len(topics) == 0 {
+		log.Warn("All deposit listeners are disabled, nothing to scan")
+		return
+	}
+
+	scanner := depositsScanner{
+		listener:  newListener(cfg, network, log, runnerName, legacyListeners...),
+		logs:      newLogsFetcher(network, log, topics),
+		websocket: network.Mode == config.ModeWebsocket,
+		msger:     rarimo.NewMessageMaker(cfg, network),
+		workers:   int(network.CatchUpWorkers),
+	}
+
+	running.WithBackOff(ctx, log, runnerName,
+		scanner.subscription,
+		5*time.Second, 5*time.Second, 5*time.Second)
+}
+
+type depositsScanner struct {
+	*listener
+	logs      *logsFetcher
+	websocket bool
+	msger     *rarimo.MessageMaker
+	workers   int
+}
+
+func (s *depositsScanner) subscription(ctx context.Context) error {
+	err := s.iterate(ctx)
+	if err != nil {
+		s.progress.Failed(err)
+	}
+
+	return err
+}
+
+func (s *depositsScanner) iterate(ctx context.Context) error {
+	active, err := s.applyControl(ctx)
+	if err != nil || !active {
+		return err
+	}
+
+	caughtUp, err := s.poll(ctx)
+	if err != nil || !caughtUp || !s.websocket {
+		return err
+	}
+
+	return s.live(ctx)
+}
+
+// poll processes the next window of final blocks. It returns true if there are no more final blocks
+// to process.
+func (s *depositsScanner) poll(ctx context.Context) (bool, error) {
+	lastFinal, ok, err := s.finality.LastFinal(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get last final block")
+	}
+
+	if !ok {
+		return true, nil
+	}
+
+	if _, err := s.checkReorg(ctx); err != nil {
+		return false, errors.Wrap(err, "failed to check processed blocks for reorg")
+	}
+
+	lastBlock := lastFinal
+
+	if lastBlock < s.fromBlock {
+		s.log.Infof("Skipping window: start %d > finish %d", s.fromBlock, lastBlock)
+		return true, nil
+	}
+
+	if s.isBehind(lastFinal) {
+		if err := s.catchUp(ctx, lastFinal); err != nil {
+			if errors.Cause(err) == errInterrupted {
+				return false, nil
+			}
+
+			return false, errors.Wrap(err, "failed to catch up")
+		}
+
+		if lastFinal < s.fromBlock {
+			return true, nil
+		}
+	}
+
+	logs, header, err := s.logs.fetch(ctx, s.fromBlock, lastBlock)
+	if err != nil {
+		s.reportRPC(metrics.WebsocketDisconnected)
+		return false, errors.Wrap(err, "failed to filter deposit events")
+	}
+
+	s.reportRPC(metrics.WebsocketAvailable)
+
+	lastBlock = header.Number.Uint64()
+
+	s.log.Infof("Processing %d events from %d to %d", len(logs), s.fromBlock, lastBlock)
+	defer s.log.Info("Subscription finished")
+
+	sortLogs(logs)
+
+	for _, raw := range logs {
+		err := s.process(ctx, raw, nil)
+		if errors.Cause(err) == errInterrupted {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, errors.Wrap(err, "failed to process event")
+		}
+	}
+
+	s.commitWindow(header)
+	return lastBlock == lastFinal, nil
+}
+
+// reportRPC sets the websocket metric from polling results. In websocket mode the metric reflects the
+// subscription state, so polling does not touch it.
+func (s *depositsScanner) reportRPC(status float64) {
+	if !s.websocket {
+		metrics.WebsocketMetric.Set(status)
+	}
+}
+
+// process handles the deposit log. The transfer message is made while handling unless it is passed.
+func (s *depositsScanner) process(ctx context.Context, raw types.Log, msg *oracletypes.MsgCreateTransferOp) error {
+	s.log.WithFields(logFields(raw)).Debug("got event")
+
+	event, err := events.Decode(raw)
+	if err == nil {
+		return s.handle(ctx, s.msger, event, msg)
+	}
+
+	if s.isHandled(raw) {
+		return nil
+	}
+
+	// the log matches a deposit topic but has a different layout, retrying will not help
+	if err := s.setAside(raw, errors.Wrap(err, "failed to decode event"), 1); err != nil {
+		return errors.Wrap(err, "failed to set event aside")
+	}
+
+	s.handled = &raw
+	return nil
+}
+
+func depositTopics(listeners config.Listeners) []common.Hash {
+	var topics []common.Hash
+
+	if listeners.Native {
+		topics = append(topics, events.INativeDepositedTopic)
+	}
+
+	if listeners.ERC20 {
+		topics = append(topics, events.IERC20DepositedTopic)
+	}
+
+	if listeners.ERC721 {
+		topics = append(topics, events.IERC721DepositedTopic)
+	}
+
+	if listeners.ERC1155 {
+		topics = append(topics, events.IERC1155DepositedTopic)
+	}
+
+	return topics
+}
+
+// sortLogs orders logs by (block, log index). Providers are not obliged to return them sorted.
+func sortLogs(logs []types.Log) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+
+		return logs[i].Index < logs[j].Index
+	})
+}
@@ -0,0 +1,203 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	"github.com/rarimo/evm-saver-svc/internal/services/cachedeth"
+	"github.com/rarimo/evm-saver-svc/internal/services/finality"
+	"github.com/rarimo/evm-saver-svc/internal/services/policy"
+	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
+	"github.com/rarimo/saver-grpc-lib/broadcaster"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Results of submitting a single deposit
+const (
+	SubmitResultSubmitted = "submitted"
+	SubmitResultOnCore    = "on_core"
+	SubmitResultRejected  = "rejected"
+)
+
+var (
+	// ErrDepositNotFound means the transaction has no deposit events of the bridge, or no log with the
+	// requested index.
+	ErrDepositNotFound = errors.New("deposit event not found")
+	// ErrForeignContract means the requested log is not emitted by the bridge contract active at its block.
+	ErrForeignContract = errors.New("log is not emitted by the active bridge contract")
+)
+
+// SubmittedDeposit is the outcome of submitting a single deposit event of the transaction.
+type SubmittedDeposit struct {
+	LogIndex       uint
+	OperationIndex string
+	Result         string
+	// Details is the broadcaster response or the deposit policy rejection
+	Details string
+	// Msg is nil if the deposit is rejected
+	Msg *oracletypes.MsgCreateTransferOp
+}
+
+// DepositSubmitter pushes deposits of a given transaction to core regardless of listeners progress,
+// checkpoints are not touched.
+type DepositSubmitter struct {
+	log         *logan.Entry
+	network     string
+	receipts    *cachedeth.Provider
+	contracts   config.Contracts
+	finality    *finality.Source
+	policy      *policy.Policy
+	msger       *rarimo.MessageMaker
+	checker     *rarimo.OperationChecker
+	broadcaster broadcaster.Broadcaster
+}
+
+func NewDepositSubmitter(cfg config.Config, network *config.Ethereum) *DepositSubmitter {
+	return &DepositSubmitter{
+		log: cfg.Log().WithFields(logan.F{
+			"who":     "deposit-submitter",
+			"network": network.NetworkName,
+		}),
+		network:     network.NetworkName,
+		receipts:    network.TxProvider,
+		contracts:   network.Contracts,
+		finality:    network.Finality,
+		policy:      cfg.Policy(),
+		msger:       rarimo.NewMessageMaker(cfg, network),
+		checker:     rarimo.NewOperationChecker(cfg, network),
+		broadcaster: cfg.Broadcaster(),
+	}
+}
+
+// Submit broadcasts transfers of the deposit with the log index, or of all bridge deposits of the
+// transaction if the index is nil. The transaction block has to be final. Deposits core already has
+// are not broadcast again. If a deposit fails, the outcomes of the preceding ones are returned with
+// the error.
+func (s *DepositSubmitter) Submit(ctx context.Context, txHash common.Hash, logIndex *uint) ([]SubmittedDeposit, error) {
+	receipt, err := s.receipts.GetTxReceipt(ctx, txHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get tx receipt")
+	}
+
+	if err := s.finality.EnsureFinal(ctx, receipt.BlockNumber.Uint64()); err != nil {
+		return nil, errors.Wrap(err, "deposit block is not final", logan.F{
+			"block": receipt.BlockNumber,
+		})
+	}
+
+	deposits, err := s.selectLogs(receipt, logIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SubmittedDeposit, 0, len(deposits))
+
+	for _, raw := range deposits {
+		deposit, err := s.submit(ctx, *raw)
+		if err != nil {
+			return result, errors.Wrap(err, "failed to submit deposit", logan.F{
+				"log_index": raw.Index,
+			})
+		}
+
+		result = append(result, deposit)
+	}
+
+	return result, nil
+}
+
+// selectLogs returns the requested log or all deposit logs of the bridge in the receipt.
+func (s *DepositSubmitter) selectLogs(receipt *types.Receipt, logIndex *uint) ([]*types.Log, error) {
+	if logIndex == nil {
+		var deposits []*types.Log
+
+		for _, raw := range receipt.Logs {
+			if s.contracts.Accepts(*raw) {
+				deposits = append(deposits, raw)
+			}
+		}
+
+		if len(deposits) == 0 {
+			return nil, errors.Wrap(ErrDepositNotFound, "tx has no deposits of the bridge")
+		}
+
+		return deposits, nil
+	}
+
+	for _, raw := range receipt.Logs {
+		if raw.Index != *logIndex {
+			continue
+		}
+
+		if !s.contracts.Accepts(*raw) {
+			return nil, errors.From(ErrForeignContract, logan.F{
+				"contract": raw.Address,
+			})
+		}
+
+		return []*types.Log{raw}, nil
+	}
+
+	return nil, errors.From(ErrDepositNotFound, logan.F{
+		"log_index": *logIndex,
+	})
+}
+
+func (s *DepositSubmitter) submit(ctx context.Context, raw types.Log) (SubmittedDeposit, error) {
+	deposit := SubmittedDeposit{
+		LogIndex:       raw.Index,
+		OperationIndex: rarimo.TransferOperationIndex(raw.TxHash.String(), fmt.Sprintf("%d", raw.Index), s.network),
+	}
+
+	log := s.log.WithFields(logFields(raw))
+
+	event, err := events.Decode(raw)
+	if err != nil {
+		return deposit, errors.Wrap(err, "failed to decode event")
+	}
+
+	rejection, err := s.policy.Check(ctx, event, s.receipts)
+	if err != nil {
+		return deposit, errors.Wrap(err, "failed to check deposit policy")
+	}
+
+	if rejection != nil {
+		log.WithField("rule", rejection.Rule).Warn("deposit is rejected by the policy")
+		deposit.Result = SubmitResultRejected
+		deposit.Details = rejection.Error()
+		return deposit, nil
+	}
+
+	deposit.Msg, err = s.msger.TransferMsg(ctx, event)
+	if err != nil {
+		return deposit, errors.Wrap(err, "failed to make transfer msg")
+	}
+
+	submitted, err := s.checker.IsSubmitted(ctx, raw)
+	if err != nil {
+		return deposit, errors.Wrap(err, "failed to check transfer on core")
+	}
+
+	if submitted {
+		log.Info("transfer is already present on core")
+		deposit.Result = SubmitResultOnCore
+		deposit.Details = "operation already exists"
+		return deposit, nil
+	}
+
+	err = s.broadcaster.BroadcastTx(ctx, deposit.Msg)
+	if err != nil {
+		return deposit, errors.Wrap(err, "failed to broadcast transfer msg")
+	}
+
+	log.Info("deposit is submitted")
+	deposit.Result = SubmitResultSubmitted
+	deposit.Details = "scheduled for broadcasting"
+	return deposit, nil
+}
@@ -0,0 +1,127 @@
+package finality
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	// BlockWindow considers a block final once block_window blocks are produced on top of it
+	BlockWindow = "block_window"
+	// Safe considers final every block up to the one with the "safe" tag
+	Safe = "safe"
+	// Finalized considers final every block up to the one with the "finalized" tag
+	Finalized = "finalized"
+)
+
+// ErrNotFinal means the block is not final yet under the configured confirmation policy.
+var ErrNotFinal = errors.New("block is not final yet")
+
+// Source tells which blocks are final under the configured confirmation policy.
+type Source struct {
+	policy string
+	window uint64
+	client *rpc.Client
+
+	// known is the latest block known to be final, blocks up to it are checked without requests
+	known uint64
+}
+
+func NewSource(policy string, window uint64, client *rpc.Client) *Source {
+	return &Source{
+		policy: policy,
+		window: window,
+		client: client,
+	}
+}
+
+func IsValidPolicy(policy string) bool {
+	return policy == BlockWindow || policy == Safe || policy == Finalized
+}
+
+func (s *Source) Policy() string {
+	return s.policy
+}
+
+// LastFinal returns the latest final block number. It returns false if there are no final blocks yet.
+func (s *Source) LastFinal(ctx context.Context) (uint64, bool, error) {
+	if s.policy == BlockWindow {
+		var head hexutil.Uint64
+		if err := s.client.CallContext(ctx, &head, "eth_blockNumber"); err != nil {
+			return 0, false, errors.Wrap(err, "failed to get recent block")
+		}
+
+		if uint64(head) < s.window {
+			return 0, false, nil
+		}
+
+		return uint64(head) - s.window, true, nil
+	}
+
+	var header *types.Header
+	if err := s.client.CallContext(ctx, &header, "eth_getBlockByNumber", s.policy, false); err != nil {
+		return 0, false, errors.Wrap(err, "failed to get tagged header", logan.F{
+			"tag": s.policy,
+		})
+	}
+
+	// nodes return null while nothing is finalized yet, e.g. right after the merge
+	if header == nil {
+		return 0, false, nil
+	}
+
+	return header.Number.Uint64(), true, nil
+}
+
+// EnsureFinal returns ErrNotFinal if the block is not final yet.
+func (s *Source) EnsureFinal(ctx context.Context, block uint64) error {
+	if block <= atomic.LoadUint64(&s.known) {
+		return nil
+	}
+
+	lastFinal, ok, err := s.LastFinal(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get last final block")
+	}
+
+	if ok {
+		s.Seen(lastFinal)
+	}
+
+	if !ok || block > lastFinal {
+		return errors.From(ErrNotFinal, logan.F{
+			"block":      block,
+			"last_final": lastFinal,
+			"policy":     s.policy,
+		})
+	}
+
+	return nil
+}
+
+// IsFinal is EnsureFinal reporting a block that is not final yet without an error.
+func (s *Source) IsFinal(ctx context.Context, block uint64) (bool, error) {
+	err := s.EnsureFinal(ctx, block)
+	if errors.Cause(err) == ErrNotFinal {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Seen remembers the block is final, e.g. because values of it have been stored as final before a
+// restart. Final blocks only move forward, so older blocks are ignored.
+func (s *Source) Seen(block uint64) {
+	for {
+		known := atomic.LoadUint64(&s.known)
+		if block <= known || atomic.CompareAndSwapUint64(&s.known, known, block) {
+			return
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package grpc
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rarimo/evm-saver-svc/internal/services/evm"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Listener controls are saved to the storage and applied by listeners on their next iteration, so they
+// survive restarts.
+
+func (s *saverService) ListControls(_ context.Context, req *api.ListControlsRequest) (*api.ListControlsResponse, error) {
+	var networks []string
+
+	if req.Network != "" {
+		if _, ok := s.networks[req.Network]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported network %s", req.Network)
+		}
+
+		networks = []string{req.Network}
+	} else {
+		for network := range s.networks {
+			networks = append(networks, network)
+		}
+
+		sort.Strings(networks)
+	}
+
+	resp := &api.ListControlsResponse{
+		Listeners: make([]*api.ListenerControl, 0, len(networks)),
+	}
+
+	for _, network := range networks {
+		control, err := s.controls.Get(network, evm.DepositsScannerName)
+		if err != nil {
+			s.log.WithError(err).Error("error getting listener control")
+			return nil, status.Error(codes.Internal, "Internal error")
+		}
+
+		listener, err := s.toListenerControl(control)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Listeners = append(resp.Listeners, listener)
+	}
+
+	return resp, nil
+}
+
+func (s *saverService) PauseListener(_ context.Context, req *api.ListenerRequest) (*api.ListenerControl, error) {
+	return s.updateControl(req.Network, req.Listener, "pause", func(control *storage.ListenerControl) {
+		control.Paused = true
+	})
+}
+
+func (s *saverService) ResumeListener(_ context.Context, req *api.ListenerRequest) (*api.ListenerControl, error) {
+	return s.updateControl(req.Network, req.Listener, "resume", func(control *storage.ListenerControl) {
+		control.Paused = false
+	})
+}
+
+func (s *saverService) PauseBroadcast(_ context.Context, req *api.ListenerRequest) (*api.ListenerControl, error) {
+	return s.updateControl(req.Network, req.Listener, "pause_broadcast", func(control *storage.ListenerControl) {
+		control.BroadcastPaused = true
+	})
+}
+
+func (s *saverService) ResumeBroadcast(_ context.Context, req *api.ListenerRequest) (*api.ListenerControl, error) {
+	return s.updateControl(req.Network, req.Listener, "resume_broadcast", func(control *storage.ListenerControl) {
+		control.BroadcastPaused = false
+	})
+}
+
+// RewindListener makes the listener continue from the block. Deposits core already has are skipped
+// when they are scanned again. The block must not be after the checkpoint, as skipping blocks forward
+// would lose their deposits.
+func (s *saverService) RewindListener(_ context.Context, req *api.RewindListenerRequest) (*api.ListenerControl, error) {
+	if req.Block == 0 {
+		return nil, status.Error(codes.InvalidArgument, "block must be positive")
+	}
+
+	network, err := s.resolveNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := resolveListener(req.Listener)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, ok, err := s.checkpoints.Get(network, listener)
+	if err != nil {
+		s.log.WithError(err).Error("error getting listener checkpoint")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition, "listener has not processed any block yet")
+	}
+
+	if req.Block > checkpoint {
+		return nil, status.Errorf(codes.InvalidArgument, "block must not be after the checkpoint %d", checkpoint)
+	}
+
+	return s.updateControl(network, listener, "rewind", func(control *storage.ListenerControl) {
+		block := req.Block
+		control.RewindTo = &block
+	})
+}
+
+func (s *saverService) updateControl(network, listener, action string, change func(control *storage.ListenerControl)) (*api.ListenerControl, error) {
+	network, err := s.resolveNetwork(network)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err = resolveListener(listener)
+	if err != nil {
+		return nil, err
+	}
+
+	control, err := s.controls.Update(network, listener, change)
+	if err != nil {
+		s.log.WithError(err).Error("error updating listener control")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	s.log.WithFields(logan.F{
+		"network":          network,
+		"listener":         listener,
+		"action":           action,
+		"paused":           control.Paused,
+		"broadcast_paused": control.BroadcastPaused,
+	}).Warn("listener control is changed")
+
+	return s.toListenerControl(control)
+}
+
+func resolveListener(listener string) (string, error) {
+	if listener == "" {
+		return evm.DepositsScannerName, nil
+	}
+
+	if listener != evm.DepositsScannerName {
+		return "", status.Errorf(codes.InvalidArgument, "unknown listener %s", listener)
+	}
+
+	return listener, nil
+}
+
+func (s *saverService) toListenerControl(control storage.ListenerControl) (*api.ListenerControl, error) {
+	buffered, err := s.buffer.Count(control.Network, control.Listener)
+	if err != nil {
+		s.log.WithError(err).Error("error counting buffered events")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	return &api.ListenerControl{
+		Network:         control.Network,
+		Listener:        control.Listener,
+		Paused:          control.Paused,
+		BroadcastPaused: control.BroadcastPaused,
+		RewindTo:        control.RewindTo,
+		Buffered:        buffered,
+		UpdatedAt:       unix(control.UpdatedAt),
+	}, nil
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+)
+
+// saverForwarder serves the saver api in the process running apart from the saver by forwarding the
+// calls to the saver, which owns the storage. Errors of the saver are returned as they are.
+type saverForwarder struct {
+	api.UnimplementedEvmSaverServer
+	saver api.EvmSaverClient
+}
+
+var _ api.EvmSaverServer = &saverForwarder{}
+
+func (f *saverForwarder) ListQuarantined(ctx context.Context, req *api.ListQuarantinedRequest) (*api.ListQuarantinedResponse, error) {
+	return f.saver.ListQuarantined(ctx, req)
+}
+
+func (f *saverForwarder) RetryQuarantined(ctx context.Context, req *api.QuarantinedEventRequest) (*api.RetryQuarantinedResponse, error) {
+	return f.saver.RetryQuarantined(ctx, req)
+}
+
+func (f *saverForwarder) DiscardQuarantined(ctx context.Context, req *api.QuarantinedEventRequest) (*api.DiscardQuarantinedResponse, error) {
+	return f.saver.DiscardQuarantined(ctx, req)
+}
+
+func (f *saverForwarder) ListRejected(ctx context.Context, req *api.ListRejectedRequest) (*api.ListRejectedResponse, error) {
+	return f.saver.ListRejected(ctx, req)
+}
+
+func (f *saverForwarder) ListListeners(ctx context.Context, req *api.ListListenersRequest) (*api.ListListenersResponse, error) {
+	return f.saver.ListListeners(ctx, req)
+}
+
+func (f *saverForwarder) SubmitDeposit(ctx context.Context, req *api.SubmitDepositRequest) (*api.SubmitDepositResponse, error) {
+	return f.saver.SubmitDeposit(ctx, req)
+}
+
+func (f *saverForwarder) GetReceiptProof(ctx context.Context, req *api.GetReceiptProofRequest) (*api.ReceiptProof, error) {
+	return f.saver.GetReceiptProof(ctx, req)
+}
+
+func (f *saverForwarder) ListControls(ctx context.Context, req *api.ListControlsRequest) (*api.ListControlsResponse, error) {
+	return f.saver.ListControls(ctx, req)
+}
+
+func (f *saverForwarder) PauseListener(ctx context.Context, req *api.ListenerRequest) (*api.ListenerControl, error) {
+	return f.saver.PauseListener(ctx, req)
+}
+
+func (f *saverForwarder) ResumeListener(ctx context.Context, req *api.ListenerRequest) (*api.ListenerControl, error) {
+	return f.saver.ResumeListener(ctx, req)
+}
+
+func (f *saverForwarder) PauseBroadcast(ctx context.Context, req *api.ListenerRequest) (*api.ListenerControl, error) {
+	return f.saver.PauseBroadcast(ctx, req)
+}
+
+func (f *saverForwarder) ResumeBroadcast(ctx context.Context, req *api.ListenerRequest) (*api.ListenerControl, error) {
+	return f.saver.ResumeBroadcast(ctx, req)
+}
+
+func (f *saverForwarder) RewindListener(ctx context.Context, req *api.RewindListenerRequest) (*api.ListenerControl, error) {
+	return f.saver.RewindListener(ctx, req)
+}
@@ -2,68 +2,192 @@ package grpc
 
 import (
 	"context"
-	"net"
+	"strings"
 
 	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo"
+	"github.com/rarimo/evm-saver-svc/internal/services/evm"
+	"github.com/rarimo/evm-saver-svc/internal/services/policy"
+	"github.com/rarimo/evm-saver-svc/internal/services/progress"
 	"github.com/rarimo/evm-saver-svc/internal/services/voting"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
 	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	"github.com/rarimo/saver-grpc-lib/broadcaster"
 	lib "github.com/rarimo/saver-grpc-lib/grpc"
-	"github.com/rarimo/saver-grpc-lib/voter"
-	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
 	"gitlab.com/distributed_lab/logan/v3"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-type saverService struct {
+// voterService revotes operations, it needs no storage, so it is served by every process running the
+// api.
+type voterService struct {
 	lib.UnimplementedSaverServer
-	log      *logan.Entry
-	voter    *voter.Voter
-	rarimo   *grpc.ClientConn
-	listener net.Listener
+	log    *logan.Entry
+	rarimo *grpc.ClientConn
+	router *voting.Router
+}
+
+// saverService exposes the saver state, so only the process owning the storage serves it.
+type saverService struct {
+	api.UnimplementedEvmSaverServer
+	log         *logan.Entry
+	networks    map[string]*network
+	quarantine  *storage.Quarantine
+	rejections  *storage.Rejections
+	controls    *storage.Controls
+	checkpoints *storage.Checkpoints
+	buffer      *storage.Buffer
+	policy      *policy.Policy
+	progress    *progress.Registry
+	broadcaster broadcaster.Broadcaster
+}
+
+// network keeps dependencies the api needs to handle events of the network.
+type network struct {
+	config    *config.Ethereum
+	msger     *rarimo.MessageMaker
+	checker   *rarimo.OperationChecker
+	senders   policy.SenderProvider
+	submitter *evm.DepositSubmitter
+	// proofs is nil if receipt proofs are not configured
+	proofs *voting.ProofKeeper
 }
 
+// RunAPI serves the api in the process running the saver along with the voter.
 func RunAPI(ctx context.Context, cfg config.Config) {
 	cfg.Log().Info("starting grpc api")
 
-	srv := grpc.NewServer()
-
-	lib.RegisterSaverServer(srv, &saverService{
-		log:      cfg.Log(),
-		rarimo:   cfg.Cosmos(),
-		listener: cfg.Listener(),
-		voter: voter.NewVoter(
-			cfg.Ethereum().NetworkName,
-			cfg.Log().WithField("who", "evm-saver-voter"),
-			cfg.Broadcaster(),
-			map[rarimotypes.OpType]voter.Verifier{
-				rarimotypes.OpType_TRANSFER: verifiers.NewTransferVerifier(
-					voting.NewTransfersVerifier(cfg),
-					cfg.Log(),
-				),
-			},
-		),
-	})
+	saver := newSaverService(cfg)
+	runServer(ctx, cfg, newVoterService(cfg, voting.RouterOpts{Proofs: cfg.Storage().Proofs()}), saver)
+}
+
+// RunSaverAPI serves the saver api only, in the process running the saver apart from the api.
+func RunSaverAPI(ctx context.Context, cfg config.Config) {
+	cfg.Log().Info("starting saver grpc api")
+
+	runServer(ctx, cfg, nil, newSaverService(cfg))
+}
+
+// RunStandaloneAPI serves the api in a process of its own. It does not open the storage, the saver api
+// calls are forwarded to the saver at saver_api.addr and answered with Unavailable if it is not set.
+func RunStandaloneAPI(ctx context.Context, cfg config.Config) {
+	cfg.Log().Info("starting grpc api")
+
+	var saver api.EvmSaverServer
+	if client := cfg.SaverAPI(); client != nil {
+		saver = &saverForwarder{saver: client}
+	} else {
+		cfg.Log().Warn("saver_api.addr is not set, the saver api is unavailable")
+	}
+
+	runServer(ctx, cfg, newVoterService(cfg, voting.RouterOpts{}), saver)
+}
+
+func newVoterService(cfg config.Config, opts voting.RouterOpts) *voterService {
+	return &voterService{
+		log:    cfg.Log(),
+		rarimo: cfg.Cosmos(),
+		router: voting.NewRouter(cfg, opts),
+	}
+}
+
+func newSaverService(cfg config.Config) *saverService {
+	service := &saverService{
+		log:         cfg.Log(),
+		networks:    make(map[string]*network),
+		quarantine:  cfg.Storage().Quarantine(),
+		rejections:  cfg.Storage().Rejections(),
+		controls:    cfg.Storage().Controls(),
+		checkpoints: cfg.Storage().Checkpoints(),
+		buffer:      cfg.Storage().Buffer(),
+		policy:      cfg.Policy(),
+		progress:    cfg.Progress(),
+		broadcaster: cfg.Broadcaster(),
+	}
+
+	for _, ethereum := range cfg.Networks() {
+		service.networks[ethereum.NetworkName] = &network{
+			config:    ethereum,
+			msger:     rarimo.NewMessageMaker(cfg, ethereum),
+			checker:   rarimo.NewOperationChecker(cfg, ethereum),
+			senders:   ethereum.TxProvider,
+			submitter: evm.NewDepositSubmitter(cfg, ethereum),
+			proofs:    voting.NewProofKeeper(cfg, ethereum, ethereum.TxProvider, cfg.Storage().Proofs()),
+		}
+	}
+
+	return service
+}
+
+// runServer serves the services that are not nil, calls of the others are answered by unknownService.
+func runServer(ctx context.Context, cfg config.Config, voter lib.SaverServer, saver api.EvmSaverServer) {
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(unknownService))
+
+	if voter != nil {
+		lib.RegisterSaverServer(srv, voter)
+	}
+
+	if saver != nil {
+		api.RegisterEvmSaverServer(srv, saver)
+
+		if addr := cfg.Status().Addr; addr != "" {
+			go serveStatus(ctx, cfg.Log(), saver, addr)
+		}
+	}
 
 	serve(ctx, srv, cfg)
 }
 
+// unknownService answers calls of services the process does not serve. The saver api is only served
+// where the storage is, so its calls are Unavailable rather than Unimplemented elsewhere.
+func unknownService(_ interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	if strings.HasPrefix(method, "/"+api.EvmSaver_ServiceDesc.ServiceName+"/") {
+		return status.Error(codes.Unavailable, "saver api is served by the saver, set saver_api.addr to reach it")
+	}
+
+	return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
 // gRPC service implementation
 
-var _ lib.SaverServer = &saverService{}
+var _ lib.SaverServer = &voterService{}
+var _ api.EvmSaverServer = &saverService{}
 
-func (s *saverService) Revote(ctx context.Context, req *lib.RevoteRequest) (*lib.RevoteResponse, error) {
+func (s *voterService) Revote(ctx context.Context, req *lib.RevoteRequest) (*lib.RevoteResponse, error) {
 	op, err := rarimotypes.NewQueryClient(s.rarimo).Operation(ctx, &rarimotypes.QueryGetOperationRequest{Index: req.Operation})
 	if err != nil {
 		s.log.WithError(err).Error("error fetching op")
 		return nil, status.Error(codes.Internal, "Internal error")
 	}
 
-	if err := s.voter.Process(ctx, op.Operation); err != nil {
+	if err := s.router.Process(ctx, op.Operation); err != nil {
 		s.log.WithError(err).Error("error processing op")
 		return nil, status.Error(codes.Internal, "Internal error")
 	}
 
 	return &lib.RevoteResponse{}, nil
 }
+
+// resolveNetwork returns the requested network name, which may be omitted if a single network is
+// configured.
+func (s *saverService) resolveNetwork(name string) (string, error) {
+	if name == "" && len(s.networks) == 1 {
+		for network := range s.networks {
+			name = network
+		}
+	}
+
+	if name == "" {
+		return "", status.Error(codes.InvalidArgument, "network must be specified, several networks are configured")
+	}
+
+	if _, ok := s.networks[name]; !ok {
+		return "", status.Errorf(codes.InvalidArgument, "unsupported network %s", name)
+	}
+
+	return name, nil
+}
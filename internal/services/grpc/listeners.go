@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/evm-saver-svc/internal/services/progress"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListListeners reports progress of listeners running in the same process together with the chain
+// head, so a stuck listener can be told apart from a network without deposits. Progress is not kept
+// anywhere else, so without listeners reported yet it is Unavailable rather than an empty list.
+func (s *saverService) ListListeners(ctx context.Context, req *api.ListListenersRequest) (*api.ListListenersResponse, error) {
+	if _, ok := s.networks[req.Network]; req.Network != "" && !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported network %s", req.Network)
+	}
+
+	listeners := s.progress.List(req.Network)
+	if len(listeners) == 0 {
+		return nil, status.Error(codes.Unavailable, "no listeners are running in the saver process yet")
+	}
+
+	resp := &api.ListListenersResponse{
+		Listeners: make([]*api.ListenerStatus, 0, len(listeners)),
+	}
+
+	heads := make(map[string]*chainHead)
+
+	for _, l := range listeners {
+		evm := s.networks[l.Network].config
+
+		head, ok := heads[l.Network]
+		if !ok {
+			head = getChainHead(ctx, evm)
+			heads[l.Network] = head
+		}
+
+		listener := &api.ListenerStatus{
+			Network:      l.Network,
+			Listener:     l.Name,
+			FromBlock:    l.FromBlock,
+			Head:         head.head,
+			LastFinal:    head.lastFinal,
+			Confirmation: evm.Confirmation,
+			BlockWindow:  evm.BlockWindow,
+			LastWindowAt: unix(l.LastWindowAt),
+			LastError:    l.LastError,
+			LastErrorAt:  unix(l.LastErrorAt),
+		}
+
+		if head.err != nil {
+			s.log.WithError(head.err).WithField("network", l.Network).Warn("error getting chain head")
+			listener.RpcError = head.err.Error()
+		}
+
+		fillLag(listener, l)
+		resp.Listeners = append(resp.Listeners, listener)
+	}
+
+	return resp, nil
+}
+
+type chainHead struct {
+	head      uint64
+	lastFinal uint64
+	err       error
+}
+
+func getChainHead(ctx context.Context, evm *config.Ethereum) *chainHead {
+	var result chainHead
+
+	head, err := evm.RPCClient.BlockNumber(ctx)
+	if err != nil {
+		result.err = errors.Wrap(err, "failed to get head block")
+		return &result
+	}
+
+	lastFinal, _, err := evm.Finality.LastFinal(ctx)
+	if err != nil {
+		result.err = errors.Wrap(err, "failed to get last final block")
+		return &result
+	}
+
+	result.head = head
+	result.lastFinal = lastFinal
+	return &result
+}
+
+func fillLag(listener *api.ListenerStatus, l progress.Listener) {
+	if listener.Head >= l.FromBlock {
+		listener.LagBlocks = listener.Head - l.FromBlock + 1
+	}
+
+	if !l.LastBlockTime.IsZero() {
+		listener.LagSeconds = int64(time.Since(l.LastBlockTime).Seconds())
+	}
+}
+
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
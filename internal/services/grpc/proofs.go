@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rarimo/evm-saver-svc/internal/services/finality"
+	"github.com/rarimo/evm-saver-svc/internal/services/receiptproof"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetReceiptProof returns the proof the vote on deposits of the transaction is based on. Transactions
+// that have not been voted on yet are proven on request.
+func (s *saverService) GetReceiptProof(ctx context.Context, req *api.GetReceiptProofRequest) (*api.ReceiptProof, error) {
+	network, err := s.resolveNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isTxHash(req.TxHash) {
+		return nil, status.Error(codes.InvalidArgument, "invalid tx hash")
+	}
+
+	keeper := s.networks[network].proofs
+	if keeper == nil {
+		return nil, status.Error(codes.FailedPrecondition, "receipt proofs are not configured for the network")
+	}
+
+	log := s.log.WithFields(logan.F{
+		"network": network,
+		"tx_hash": req.TxHash,
+	})
+
+	proof, err := keeper.Get(ctx, common.HexToHash(req.TxHash))
+	if err != nil {
+		log.WithError(err).Error("error getting receipt proof")
+
+		switch errors.Cause(err) {
+		case finality.ErrNotFinal:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case receiptproof.ErrInconsistent, receiptproof.ErrNotCanonical, receiptproof.ErrNotIncluded:
+			return nil, status.Error(codes.DataLoss, err.Error())
+		default:
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
+	resp := &api.ReceiptProof{
+		Network:       proof.Network,
+		TxHash:        proof.TxHash.Hex(),
+		BlockNumber:   proof.BlockNumber,
+		BlockHash:     proof.BlockHash.Hex(),
+		Header:        proof.Header.String(),
+		TxIndex:       uint64(proof.TxIndex),
+		FirstLogIndex: uint64(proof.FirstLogIndex),
+		TxProof:       make([]string, len(proof.TxProof)),
+		ReceiptProof:  make([]string, len(proof.ReceiptProof)),
+		ProvenAt:      proof.ProvenAt.Unix(),
+	}
+
+	for i, node := range proof.TxProof {
+		resp.TxProof[i] = node.String()
+	}
+
+	for i, node := range proof.ReceiptProof {
+		resp.ReceiptProof[i] = node.String()
+	}
+
+	if req.LogIndex == nil {
+		return resp, nil
+	}
+
+	receipt, err := receiptproof.Verify(*proof)
+	if err != nil {
+		log.WithError(err).Error("kept receipt proof is invalid")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	index := *req.LogIndex
+	if index < uint64(proof.FirstLogIndex) || index >= uint64(proof.FirstLogIndex)+uint64(len(receipt.Logs)) {
+		return nil, status.Error(codes.InvalidArgument, "log is not in the transaction receipt")
+	}
+
+	rawLog, err := json.Marshal(receipt.Logs[index-uint64(proof.FirstLogIndex)])
+	if err != nil {
+		log.WithError(err).Error("error marshaling proven log")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	resp.RawLog = string(rawLog)
+	return resp, nil
+}
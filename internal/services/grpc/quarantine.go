@@ -0,0 +1,166 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *saverService) ListQuarantined(_ context.Context, req *api.ListQuarantinedRequest) (*api.ListQuarantinedResponse, error) {
+	quarantined, err := s.quarantine.List(req.Network)
+	if err != nil {
+		s.log.WithError(err).Error("error listing quarantined events")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	resp := &api.ListQuarantinedResponse{
+		Events: make([]*api.QuarantinedEvent, 0, len(quarantined)),
+	}
+
+	for _, event := range quarantined {
+		rawLog, err := json.Marshal(event.Log)
+		if err != nil {
+			s.log.WithError(err).Error("error marshaling quarantined log")
+			return nil, status.Error(codes.Internal, "Internal error")
+		}
+
+		resp.Events = append(resp.Events, &api.QuarantinedEvent{
+			Network:       event.Network,
+			Listener:      event.Listener,
+			TxHash:        event.Log.TxHash.String(),
+			LogIndex:      uint64(event.Log.Index),
+			Block:         event.Log.BlockNumber,
+			Error:         event.Error,
+			Attempts:      event.Attempts,
+			RawLog:        string(rawLog),
+			QuarantinedAt: event.QuarantinedAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+// RetryQuarantined rebuilds the transfer message from the quarantined log and broadcasts it. The event
+// leaves the quarantine only if the broadcast succeeds or core turns out to have the transfer already.
+func (s *saverService) RetryQuarantined(ctx context.Context, req *api.QuarantinedEventRequest) (*api.RetryQuarantinedResponse, error) {
+	event, err := s.getQuarantined(req)
+	if err != nil {
+		return nil, err
+	}
+
+	log := s.log.WithFields(logan.F{
+		"tx_hash":   event.Log.TxHash,
+		"log_index": event.Log.Index,
+	})
+
+	decoded, err := events.Decode(event.Log)
+	if err != nil {
+		log.WithError(err).Error("error decoding quarantined log")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	evm := s.networks[event.Network]
+
+	rejection, err := s.policy.Check(ctx, decoded, evm.senders)
+	if err != nil {
+		log.WithError(err).Error("error checking deposit policy for quarantined event")
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	if rejection != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "event is rejected by the deposit policy: %s", rejection.Error())
+	}
+
+	msg, err := evm.msger.TransferMsg(ctx, decoded)
+	if err != nil {
+		s.updateQuarantined(event, err)
+		log.WithError(err).Error("error making transfer msg for quarantined event")
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	submitted, err := evm.checker.IsSubmitted(ctx, event.Log)
+	if err != nil {
+		log.WithError(err).Error("error checking quarantined event on core")
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	if submitted {
+		log.Info("quarantined event is already present on core")
+	} else {
+		err = s.broadcaster.BroadcastTx(ctx, msg)
+	}
+
+	if err != nil {
+		s.updateQuarantined(event, err)
+		log.WithError(err).Error("error broadcasting transfer msg for quarantined event")
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	if err := s.quarantine.Delete(event.Network, event.Log.TxHash.String(), event.Log.Index); err != nil {
+		log.WithError(err).Error("error deleting retried event from quarantine")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	rawMsg, err := json.Marshal(msg)
+	if err != nil {
+		log.WithError(err).Error("error marshaling transfer msg")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	log.Info("quarantined event is retried successfully")
+	return &api.RetryQuarantinedResponse{Msg: string(rawMsg)}, nil
+}
+
+func (s *saverService) DiscardQuarantined(_ context.Context, req *api.QuarantinedEventRequest) (*api.DiscardQuarantinedResponse, error) {
+	event, err := s.getQuarantined(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.quarantine.Delete(event.Network, event.Log.TxHash.String(), event.Log.Index); err != nil {
+		s.log.WithError(err).Error("error deleting quarantined event")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	s.log.WithFields(logan.F{
+		"tx_hash":   event.Log.TxHash,
+		"log_index": event.Log.Index,
+	}).Warn("quarantined event is discarded")
+
+	return &api.DiscardQuarantinedResponse{}, nil
+}
+
+func (s *saverService) getQuarantined(req *api.QuarantinedEventRequest) (*storage.QuarantinedEvent, error) {
+	network, err := s.resolveNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.quarantine.Get(network, common.HexToHash(req.TxHash).String(), uint(req.LogIndex))
+	if err != nil {
+		s.log.WithError(err).Error("error getting quarantined event")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	if event == nil {
+		return nil, status.Error(codes.NotFound, "event is not quarantined")
+	}
+
+	return event, nil
+}
+
+func (s *saverService) updateQuarantined(event *storage.QuarantinedEvent, cause error) {
+	event.Attempts++
+	event.Error = cause.Error()
+
+	if err := s.quarantine.Put(*event); err != nil {
+		s.log.WithError(err).Error("error updating quarantined event")
+	}
+}
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *saverService) ListRejected(_ context.Context, req *api.ListRejectedRequest) (*api.ListRejectedResponse, error) {
+	rejected, err := s.rejections.List(req.Network)
+	if err != nil {
+		s.log.WithError(err).Error("error listing rejected events")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	resp := &api.ListRejectedResponse{
+		Events: make([]*api.RejectedEvent, 0, len(rejected)),
+	}
+
+	for _, event := range rejected {
+		rawLog, err := json.Marshal(event.Log)
+		if err != nil {
+			s.log.WithError(err).Error("error marshaling rejected log")
+			return nil, status.Error(codes.Internal, "Internal error")
+		}
+
+		resp.Events = append(resp.Events, &api.RejectedEvent{
+			Network:    event.Network,
+			Listener:   event.Listener,
+			TxHash:     event.Log.TxHash.String(),
+			LogIndex:   uint64(event.Log.Index),
+			Block:      event.Log.BlockNumber,
+			Rule:       event.Rule,
+			Details:    event.Details,
+			RawLog:     string(rawLog),
+			RejectedAt: event.RejectedAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// serveStatus exposes listeners progress over http for tools that can not speak grpc. The response is
+// the same as the one of ListListeners encoded as JSON.
+func serveStatus(ctx context.Context, log *logan.Entry, saver api.EvmSaverServer, addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/listeners", listenersHandler(log, saver))
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.WithError(err).Error("failed to shutdown status server")
+		}
+	}()
+
+	log.WithField("addr", addr).Info("starting status server")
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.WithError(err).Error("status server died")
+	}
+}
+
+func listenersHandler(log *logan.Entry, saver api.EvmSaverServer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		resp, err := saver.ListListeners(r.Context(), &api.ListListenersRequest{
+			Network: r.URL.Query().Get("network"),
+		})
+		if err != nil {
+			st := status.Convert(err)
+			http.Error(w, st.Message(), httpStatus(st.Code()))
+			return
+		}
+
+		body, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(resp)
+		if err != nil {
+			log.WithError(err).Error("error marshaling listeners")
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(body); err != nil {
+			log.WithError(err).Debug("failed to write listeners response")
+		}
+	}
+}
+
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rarimo/evm-saver-svc/internal/services/evm"
+	"github.com/rarimo/evm-saver-svc/internal/services/finality"
+	api "github.com/rarimo/evm-saver-svc/pkg/grpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SubmitDeposit pushes deposits of the transaction to core without touching listeners checkpoints.
+func (s *saverService) SubmitDeposit(ctx context.Context, req *api.SubmitDepositRequest) (*api.SubmitDepositResponse, error) {
+	network, err := s.resolveNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isTxHash(req.TxHash) {
+		return nil, status.Error(codes.InvalidArgument, "invalid tx hash")
+	}
+
+	var logIndex *uint
+	if req.LogIndex != nil {
+		index := uint(*req.LogIndex)
+		logIndex = &index
+	}
+
+	log := s.log.WithFields(logan.F{
+		"network": network,
+		"tx_hash": req.TxHash,
+	})
+
+	deposits, err := s.networks[network].submitter.Submit(ctx, common.HexToHash(req.TxHash), logIndex)
+	if err != nil {
+		log.WithError(err).Error("error submitting deposit")
+
+		switch errors.Cause(err) {
+		case evm.ErrDepositNotFound:
+			return nil, status.Error(codes.NotFound, err.Error())
+		case evm.ErrForeignContract:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case finality.ErrNotFinal:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
+	resp := &api.SubmitDepositResponse{
+		Deposits: make([]*api.SubmittedDeposit, 0, len(deposits)),
+	}
+
+	for _, deposit := range deposits {
+		submitted := &api.SubmittedDeposit{
+			LogIndex:       uint64(deposit.LogIndex),
+			OperationIndex: deposit.OperationIndex,
+			Result:         deposit.Result,
+			Details:        deposit.Details,
+		}
+
+		if deposit.Msg != nil {
+			rawMsg, err := json.Marshal(deposit.Msg)
+			if err != nil {
+				log.WithError(err).Error("error marshaling transfer msg")
+				return nil, status.Error(codes.Internal, "Internal error")
+			}
+
+			submitted.Msg = string(rawMsg)
+		}
+
+		resp.Deposits = append(resp.Deposits, submitted)
+	}
+
+	return resp, nil
+}
+
+func isTxHash(hash string) bool {
+	return len(common.FromHex(hash)) == common.HashLength
+}
@@ -0,0 +1,94 @@
+package policy
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// blocklist is a set of addresses loaded from a file with one address per line, lines starting with #
+// are comments. The file is loaded again once it is modified, so addresses can be blocked without
+// restarting the service.
+type blocklist struct {
+	path string
+
+	mu        sync.Mutex
+	modTime   time.Time
+	addresses map[string]struct{}
+}
+
+func newBlocklist(path string) (*blocklist, error) {
+	b := &blocklist{path: path}
+
+	if err := b.reload(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (b *blocklist) contains(addr string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.reload(); err != nil {
+		return false, err
+	}
+
+	_, ok := b.addresses[normalize(addr)]
+	return ok, nil
+}
+
+// reload reads the file if it has been modified since the last read.
+func (b *blocklist) reload() error {
+	info, err := os.Stat(b.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to stat blocklist file", logan.F{
+			"path": b.path,
+		})
+	}
+
+	if b.addresses != nil && info.ModTime().Equal(b.modTime) {
+		return nil
+	}
+
+	file, err := os.Open(b.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open blocklist file", logan.F{
+			"path": b.path,
+		})
+	}
+	defer file.Close()
+
+	addresses := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		addresses[normalize(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "failed to read blocklist file", logan.F{
+			"path": b.path,
+		})
+	}
+
+	b.addresses = addresses
+	b.modTime = info.ModTime()
+	return nil
+}
+
+// normalize makes hex addresses comparable regardless of checksum case.
+func normalize(addr string) string {
+	return strings.ToLower(strings.TrimSpace(addr))
+}
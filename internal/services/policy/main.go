@@ -0,0 +1,177 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Rules deposit events can be rejected by
+const (
+	RuleTokenNotAllowed       = "token_not_allowed"
+	RuleTokenDenied           = "token_denied"
+	RuleDestinationNotAllowed = "destination_not_allowed"
+	RuleAmountBelowMin        = "amount_below_min"
+	RuleAmountAboveMax        = "amount_above_max"
+	RuleSenderBlocked         = "sender_blocked"
+	RuleReceiverBlocked       = "receiver_blocked"
+)
+
+type SenderProvider interface {
+	GetTx(ctx context.Context, hash common.Hash) (*types.Transaction, string, error)
+}
+
+// AmountLimits bounds deposit amounts of a token, nil bound is not checked.
+type AmountLimits struct {
+	Min *big.Int `fig:"min"`
+	Max *big.Int `fig:"max"`
+}
+
+// Rules is the oracle side policy configuration. Empty allowlists allow everything. Native deposits
+// have the zero token address. Token rules are not bound to a network, they apply to deposits of every
+// configured network, so a token address listed for one network matches the same address on the others.
+type Rules struct {
+	AllowedTokens       []string                `fig:"allowed_tokens"`
+	DeniedTokens        []string                `fig:"denied_tokens"`
+	DestinationNetworks []string                `fig:"destination_networks"`
+	Amounts             map[string]AmountLimits `fig:"amounts"`
+	// BlocklistFile keeps blocked sender and receiver addresses, one per line
+	BlocklistFile string `fig:"blocklist_file"`
+}
+
+// Rejection is the rule a deposit event breaks.
+type Rejection struct {
+	Rule    string
+	Details string
+}
+
+func (r *Rejection) Error() string {
+	return fmt.Sprintf("%s: %s", r.Rule, r.Details)
+}
+
+// Policy decides whether deposit events may be turned into transfers.
+type Policy struct {
+	allowedTokens map[common.Address]struct{}
+	deniedTokens  map[common.Address]struct{}
+	destinations  map[string]struct{}
+	amounts       map[common.Address]AmountLimits
+	blocklist     *blocklist
+}
+
+func New(rules Rules) (*Policy, error) {
+	allowedTokens, err := addressSet(rules.AllowedTokens)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid allowed tokens")
+	}
+
+	deniedTokens, err := addressSet(rules.DeniedTokens)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid denied tokens")
+	}
+
+	p := &Policy{
+		allowedTokens: allowedTokens,
+		deniedTokens:  deniedTokens,
+		destinations:  make(map[string]struct{}, len(rules.DestinationNetworks)),
+		amounts:       make(map[common.Address]AmountLimits, len(rules.Amounts)),
+	}
+
+	for _, network := range rules.DestinationNetworks {
+		p.destinations[network] = struct{}{}
+	}
+
+	for token, limits := range rules.Amounts {
+		if !common.IsHexAddress(token) {
+			return nil, errors.Errorf("invalid token address %s in amount limits", token)
+		}
+
+		p.amounts[common.HexToAddress(token)] = limits
+	}
+
+	if rules.BlocklistFile != "" {
+		p.blocklist, err = newBlocklist(rules.BlocklistFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load blocklist")
+		}
+	}
+
+	return p, nil
+}
+
+// Check returns the rejection if the event breaks any rule. Senders are looked up in the network the
+// event comes from.
+func (p *Policy) Check(ctx context.Context, event events.Event, senders SenderProvider) (*Rejection, error) {
+	token := event.Token()
+
+	if _, ok := p.deniedTokens[token]; ok {
+		return &Rejection{Rule: RuleTokenDenied, Details: token.String()}, nil
+	}
+
+	if _, ok := p.allowedTokens[token]; len(p.allowedTokens) > 0 && !ok {
+		return &Rejection{Rule: RuleTokenNotAllowed, Details: token.String()}, nil
+	}
+
+	if _, ok := p.destinations[event.Network()]; len(p.destinations) > 0 && !ok {
+		return &Rejection{Rule: RuleDestinationNotAllowed, Details: event.Network()}, nil
+	}
+
+	if limits, ok := p.amounts[token]; ok && event.Amount() != nil {
+		if limits.Min != nil && event.Amount().Cmp(limits.Min) < 0 {
+			return &Rejection{Rule: RuleAmountBelowMin, Details: fmt.Sprintf("%s < %s", event.Amount(), limits.Min)}, nil
+		}
+
+		if limits.Max != nil && event.Amount().Cmp(limits.Max) > 0 {
+			return &Rejection{Rule: RuleAmountAboveMax, Details: fmt.Sprintf("%s > %s", event.Amount(), limits.Max)}, nil
+		}
+	}
+
+	if p.blocklist == nil {
+		return nil, nil
+	}
+
+	blocked, err := p.blocklist.contains(event.Receiver())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check receiver")
+	}
+
+	if blocked {
+		return &Rejection{Rule: RuleReceiverBlocked, Details: event.Receiver()}, nil
+	}
+
+	_, sender, err := senders.GetTx(ctx, event.Raw().TxHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get deposit sender")
+	}
+
+	blocked, err = p.blocklist.contains(sender)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check sender")
+	}
+
+	if blocked {
+		return &Rejection{Rule: RuleSenderBlocked, Details: sender}, nil
+	}
+
+	return nil, nil
+}
+
+func addressSet(addresses []string) (map[common.Address]struct{}, error) {
+	result := make(map[common.Address]struct{}, len(addresses))
+
+	for _, addr := range addresses {
+		addr = strings.TrimSpace(addr)
+		if !common.IsHexAddress(addr) {
+			return nil, errors.Errorf("invalid token address %s", addr)
+		}
+
+		result[common.HexToAddress(addr)] = struct{}{}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package progress
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Listener is the progress of the listener as it has last reported it.
+type Listener struct {
+	Network string
+	Name    string
+	// FromBlock is the next block to be processed
+	FromBlock uint64
+	// LastBlockTime is the timestamp of the last processed block, zero until a window is processed
+	LastBlockTime time.Time
+	// LastWindowAt is when the last window has been processed successfully
+	LastWindowAt time.Time
+	LastError    string
+	LastErrorAt  time.Time
+}
+
+// Registry keeps progress of listeners running in the process, so it can be reported by the api.
+type Registry struct {
+	mu        sync.RWMutex
+	listeners map[string]*Listener
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		listeners: make(map[string]*Listener),
+	}
+}
+
+// Tracker reports progress of a single listener.
+type Tracker struct {
+	registry *Registry
+	key      string
+}
+
+// Track registers the listener starting from the block.
+func (r *Registry) Track(network, name string, fromBlock uint64) *Tracker {
+	key := network + "/" + name
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.listeners[key] = &Listener{
+		Network:   network,
+		Name:      name,
+		FromBlock: fromBlock,
+	}
+
+	return &Tracker{registry: r, key: key}
+}
+
+// List returns copies of all listeners ordered by network and name, only the given network ones if it is
+// not empty.
+func (r *Registry) List(network string) []Listener {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]Listener, 0, len(r.listeners))
+
+	for _, l := range r.listeners {
+		if network == "" || l.Network == network {
+			result = append(result, *l)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Network != result[j].Network {
+			return result[i].Network < result[j].Network
+		}
+
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func (t *Tracker) update(f func(l *Listener)) {
+	t.registry.mu.Lock()
+	defer t.registry.mu.Unlock()
+
+	f(t.registry.listeners[t.key])
+}
+
+// Moved reports the next block to be processed.
+func (t *Tracker) Moved(fromBlock uint64) {
+	t.update(func(l *Listener) {
+		l.FromBlock = fromBlock
+	})
+}
+
+// Processed reports the window ending with the block of the given timestamp is processed.
+func (t *Tracker) Processed(blockTime uint64) {
+	t.update(func(l *Listener) {
+		l.LastBlockTime = time.Unix(int64(blockTime), 0)
+		l.LastWindowAt = time.Now()
+	})
+}
+
+// Failed reports the error the listener has stopped the iteration with.
+func (t *Tracker) Failed(err error) {
+	t.update(func(l *Listener) {
+		l.LastError = err.Error()
+		l.LastErrorAt = time.Now()
+	})
+}
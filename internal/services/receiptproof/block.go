@@ -0,0 +1,201 @@
+package receiptproof
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// rawList is a list of consensus encodings the block tries are built of.
+type rawList [][]byte
+
+func (l rawList) Len() int {
+	return len(l)
+}
+
+func (l rawList) EncodeIndex(i int, w *bytes.Buffer) {
+	w.Write(l[i])
+}
+
+// rawTransactions returns consensus encodings of the block transactions. Transactions of types unknown
+// to the go-ethereum version in use can not be encoded from json, so they are requested raw then.
+func rawTransactions(ctx context.Context, client *rpc.Client, block *rpcBlock) (rawList, error) {
+	txs := make(rawList, len(block.Transactions))
+
+	for i, raw := range block.Transactions {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalJSON(raw); err != nil {
+			return requestRawTransactions(ctx, client, block)
+		}
+
+		encoded, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode transaction", logan.F{
+				"tx_index": i,
+			})
+		}
+
+		txs[i] = encoded
+	}
+
+	return txs, nil
+}
+
+func requestRawTransactions(ctx context.Context, client *rpc.Client, block *rpcBlock) (rawList, error) {
+	results := make([]hexutil.Bytes, len(block.Transactions))
+	batch := make([]rpc.BatchElem, len(block.Transactions))
+
+	for i := range batch {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getRawTransactionByBlockHashAndIndex",
+			Args:   []interface{}{block.Hash, hexutil.Uint64(i)},
+			Result: &results[i],
+		}
+	}
+
+	if err := client.BatchCallContext(ctx, batch); err != nil {
+		return nil, errors.Wrap(err, "failed to get raw transactions")
+	}
+
+	txs := make(rawList, len(results))
+
+	for i := range batch {
+		if batch[i].Error != nil {
+			return nil, errors.Wrap(batch[i].Error, "failed to get raw transaction", logan.F{
+				"tx_index": i,
+			})
+		}
+
+		txs[i] = results[i]
+	}
+
+	return txs, nil
+}
+
+// blockReceipts returns receipts of the block transactions. eth_getBlockReceipts is not supported by
+// every node, so receipts are requested one by one if the node rejects it.
+func blockReceipts(ctx context.Context, client *rpc.Client, blockHash common.Hash, txHashes []common.Hash) ([]*types.Receipt, error) {
+	var receipts []*types.Receipt
+
+	err := client.CallContext(ctx, &receipts, "eth_getBlockReceipts", blockHash)
+	if err == nil {
+		return receipts, nil
+	}
+
+	if _, ok := err.(rpc.Error); !ok {
+		return nil, errors.Wrap(err, "failed to get block receipts")
+	}
+
+	receipts = make([]*types.Receipt, len(txHashes))
+	batch := make([]rpc.BatchElem, len(txHashes))
+
+	for i, hash := range txHashes {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{hash},
+			Result: &receipts[i],
+		}
+	}
+
+	if err := client.BatchCallContext(ctx, batch); err != nil {
+		return nil, errors.Wrap(err, "failed to get receipts")
+	}
+
+	for i := range batch {
+		if batch[i].Error != nil {
+			return nil, errors.Wrap(batch[i].Error, "failed to get receipt", logan.F{
+				"tx_hash": txHashes[i],
+			})
+		}
+
+		if receipts[i] == nil {
+			return nil, errors.From(errors.New("receipt not found"), logan.F{
+				"tx_hash": txHashes[i],
+			})
+		}
+	}
+
+	return receipts, nil
+}
+
+// receiptConsensus is the part of a receipt the receipts trie commits to.
+type receiptConsensus struct {
+	// PostStateOrStatus is the state root before byzantium and the status after it
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Bloom             types.Bloom
+	Logs              []*types.Log
+}
+
+var (
+	receiptStatusFailed     = []byte{}
+	receiptStatusSuccessful = []byte{0x01}
+)
+
+// encodeReceipt returns the consensus encoding of the receipt. It is the same for every typed receipt,
+// so receipts of transaction types unknown to the go-ethereum version in use are encoded as well.
+func encodeReceipt(receipt *types.Receipt) ([]byte, error) {
+	consensus := receiptConsensus{
+		PostStateOrStatus: receipt.PostState,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+		Bloom:             receipt.Bloom,
+		Logs:              receipt.Logs,
+	}
+
+	if len(receipt.PostState) == 0 {
+		consensus.PostStateOrStatus = receiptStatusFailed
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			consensus.PostStateOrStatus = receiptStatusSuccessful
+		}
+	}
+
+	payload, err := rlp.EncodeToBytes(&consensus)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode receipt")
+	}
+
+	if receipt.Type == types.LegacyTxType {
+		return payload, nil
+	}
+
+	return append([]byte{receipt.Type}, payload...), nil
+}
+
+func decodeReceipt(raw []byte) (*types.Receipt, error) {
+	receipt := new(types.Receipt)
+
+	// typed receipts start with the type, legacy ones with an rlp list prefix
+	if len(raw) > 0 && raw[0] <= 0x7f {
+		receipt.Type = raw[0]
+		raw = raw[1:]
+	}
+
+	var consensus receiptConsensus
+	if err := rlp.DecodeBytes(raw, &consensus); err != nil {
+		return nil, errors.Wrap(err, "failed to decode receipt")
+	}
+
+	switch {
+	case len(consensus.PostStateOrStatus) == common.HashLength:
+		receipt.PostState = consensus.PostStateOrStatus
+	case bytes.Equal(consensus.PostStateOrStatus, receiptStatusSuccessful):
+		receipt.Status = types.ReceiptStatusSuccessful
+	case bytes.Equal(consensus.PostStateOrStatus, receiptStatusFailed):
+		receipt.Status = types.ReceiptStatusFailed
+	default:
+		return nil, errors.New("invalid receipt status")
+	}
+
+	receipt.CumulativeGasUsed = consensus.CumulativeGasUsed
+	receipt.Bloom = consensus.Bloom
+	receipt.Logs = consensus.Logs
+
+	return receipt, nil
+}
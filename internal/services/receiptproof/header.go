@@ -0,0 +1,152 @@
+package receiptproof
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Positions of header fields in its rlp list
+const (
+	headerParentHash  = 0
+	headerTxHash      = 4
+	headerReceiptHash = 5
+	headerNumber      = 8
+)
+
+// rpcBlock is the block as nodes return it. The header is encoded from its json fields instead of
+// types.Header, so fields added by later forks are still hashed, the optional ones are encoded only
+// if they are set.
+type rpcBlock struct {
+	Hash             common.Hash       `json:"hash"`
+	ParentHash       common.Hash       `json:"parentHash"`
+	UncleHash        common.Hash       `json:"sha3Uncles"`
+	Coinbase         common.Address    `json:"miner"`
+	Root             common.Hash       `json:"stateRoot"`
+	TxHash           common.Hash       `json:"transactionsRoot"`
+	ReceiptHash      common.Hash       `json:"receiptsRoot"`
+	Bloom            types.Bloom       `json:"logsBloom"`
+	Difficulty       *hexutil.Big      `json:"difficulty"`
+	Number           *hexutil.Big      `json:"number"`
+	GasLimit         hexutil.Uint64    `json:"gasLimit"`
+	GasUsed          hexutil.Uint64    `json:"gasUsed"`
+	Time             hexutil.Uint64    `json:"timestamp"`
+	Extra            hexutil.Bytes     `json:"extraData"`
+	MixDigest        common.Hash       `json:"mixHash"`
+	Nonce            types.BlockNonce  `json:"nonce"`
+	BaseFee          *hexutil.Big      `json:"baseFeePerGas"`
+	WithdrawalsHash  *common.Hash      `json:"withdrawalsRoot"`
+	BlobGasUsed      *hexutil.Uint64   `json:"blobGasUsed"`
+	ExcessBlobGas    *hexutil.Uint64   `json:"excessBlobGas"`
+	ParentBeaconRoot *common.Hash      `json:"parentBeaconBlockRoot"`
+	RequestsHash     *common.Hash      `json:"requestsHash"`
+	Transactions     []json.RawMessage `json:"transactions"`
+}
+
+// encodeHeader returns the rlp of the header, its keccak hash is the block hash.
+func (b *rpcBlock) encodeHeader() ([]byte, error) {
+	if b.Difficulty == nil || b.Number == nil {
+		return nil, errors.New("header misses difficulty or number")
+	}
+
+	fields := []interface{}{
+		b.ParentHash, b.UncleHash, b.Coinbase, b.Root, b.TxHash, b.ReceiptHash, b.Bloom,
+		(*big.Int)(b.Difficulty), (*big.Int)(b.Number), uint64(b.GasLimit), uint64(b.GasUsed),
+		uint64(b.Time), []byte(b.Extra), b.MixDigest, b.Nonce,
+	}
+
+	// fields of a fork are set only after it, and every fork keeps the fields of the previous ones
+	if b.BaseFee != nil {
+		fields = append(fields, (*big.Int)(b.BaseFee))
+	}
+	if b.WithdrawalsHash != nil {
+		fields = append(fields, *b.WithdrawalsHash)
+	}
+	if b.BlobGasUsed != nil {
+		fields = append(fields, uint64(*b.BlobGasUsed))
+	}
+	if b.ExcessBlobGas != nil {
+		fields = append(fields, uint64(*b.ExcessBlobGas))
+	}
+	if b.ParentBeaconRoot != nil {
+		fields = append(fields, *b.ParentBeaconRoot)
+	}
+	if b.RequestsHash != nil {
+		fields = append(fields, *b.RequestsHash)
+	}
+
+	return rlp.EncodeToBytes(fields)
+}
+
+// getBlock requests the block and checks its header hashes to the block hash, so the header fields can
+// be trusted as much as the hash.
+func getBlock(ctx context.Context, client *rpc.Client, method string, id interface{}, fullTxs bool) (*rpcBlock, []byte, error) {
+	var block *rpcBlock
+	if err := client.CallContext(ctx, &block, method, id, fullTxs); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get block")
+	}
+
+	if block == nil {
+		return nil, nil, errors.From(errors.New("block not found"), logan.F{
+			"block": id,
+		})
+	}
+
+	header, err := block.encodeHeader()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode header")
+	}
+
+	if hash := crypto.Keccak256Hash(header); hash != block.Hash {
+		return nil, nil, errors.From(ErrInconsistent, logan.F{
+			"reason":      "header does not hash to the block hash",
+			"block_hash":  block.Hash,
+			"header_hash": hash,
+		})
+	}
+
+	return block, header, nil
+}
+
+// headerFields returns the fields of the header rlp.
+func headerFields(header []byte) ([]rlp.RawValue, error) {
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(header, &fields); err != nil {
+		return nil, errors.Wrap(err, "failed to decode header")
+	}
+
+	if len(fields) <= headerNumber {
+		return nil, errors.New("header has too few fields")
+	}
+
+	return fields, nil
+}
+
+func headerHash(fields []rlp.RawValue, i int) (common.Hash, error) {
+	var hash common.Hash
+	if err := rlp.DecodeBytes(fields[i], &hash); err != nil {
+		return common.Hash{}, errors.Wrap(err, "failed to decode header hash field", logan.F{
+			"field": i,
+		})
+	}
+
+	return hash, nil
+}
+
+func headerNumberOf(fields []rlp.RawValue) (uint64, error) {
+	var number uint64
+	if err := rlp.DecodeBytes(fields[headerNumber], &number); err != nil {
+		return 0, errors.Wrap(err, "failed to decode header number")
+	}
+
+	return number, nil
+}
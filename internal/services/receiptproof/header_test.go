@@ -0,0 +1,238 @@
+package receiptproof
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var emptyBloom = "0x" + common.Bytes2Hex(make([]byte, types.BloomByteLength))
+
+// mainnet blocks as eth_getBlockByNumber returns them
+var (
+	mainnetGenesis = `{
+		"hash": "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		"parentHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"sha3Uncles": "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+		"miner": "0x0000000000000000000000000000000000000000",
+		"stateRoot": "0xd7f8974fb5ac78d9ac099b9ad5018bedc2ce0a72dad1827a1709da30580f0544",
+		"transactionsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"receiptsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"logsBloom": "` + emptyBloom + `",
+		"difficulty": "0x400000000",
+		"number": "0x0",
+		"gasLimit": "0x1388",
+		"gasUsed": "0x0",
+		"timestamp": "0x0",
+		"extraData": "0x11bbe8db4e347b4e8c937c1c8370e4b5ed33adb3db69cbdb7a38e1e50b1b82fa",
+		"mixHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"nonce": "0x0000000000000042"
+	}`
+	mainnetBlock1 = `{
+		"hash": "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6",
+		"parentHash": "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		"sha3Uncles": "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+		"miner": "0x05a56e2d52c817161883f50c441c3228cfe54d9f",
+		"stateRoot": "0xd67e4d450343046425ae4271474353857ab860dbc0a1dde64b41b5cd3a532bf3",
+		"transactionsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"receiptsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"logsBloom": "` + emptyBloom + `",
+		"difficulty": "0x3ff800000",
+		"number": "0x1",
+		"gasLimit": "0x1388",
+		"gasUsed": "0x0",
+		"timestamp": "0x55ba4224",
+		"extraData": "0x476574682f76312e302e302f6c696e75782f676f312e342e32",
+		"mixHash": "0x969b900de27b6ac6a67742365dd65f55a0526c41fd18e1b16f1a1215c2e66f59",
+		"nonce": "0x539bd4979fef1ec4"
+	}`
+)
+
+func TestEncodeHeaderMainnet(t *testing.T) {
+	for name, raw := range map[string]string{"genesis": mainnetGenesis, "block 1": mainnetBlock1} {
+		t.Run(name, func(t *testing.T) {
+			var block rpcBlock
+			if err := json.Unmarshal([]byte(raw), &block); err != nil {
+				t.Fatal(err)
+			}
+
+			header, err := block.encodeHeader()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if hash := crypto.Keccak256Hash(header); hash != block.Hash {
+				t.Errorf("header hash = %s, want %s", hash, block.Hash)
+			}
+		})
+	}
+}
+
+func TestEncodeHeaderForks(t *testing.T) {
+	hash := func(b byte) *common.Hash {
+		h := common.BytesToHash([]byte{b})
+		return &h
+	}
+	uint64Ptr := func(v uint64) *hexutil.Uint64 {
+		return (*hexutil.Uint64)(&v)
+	}
+
+	london := testHeader()
+	london.BaseFee = big.NewInt(7)
+
+	cases := []struct {
+		name  string
+		fork  func(b *rpcBlock)
+		want  *types.Header
+		extra []interface{}
+	}{
+		{
+			name: "frontier",
+			fork: func(b *rpcBlock) {},
+			want: testHeader(),
+		},
+		{
+			name: "london",
+			fork: func(b *rpcBlock) { b.BaseFee = (*hexutil.Big)(big.NewInt(7)) },
+			want: london,
+		},
+		{
+			name: "shanghai",
+			fork: func(b *rpcBlock) {
+				b.BaseFee = (*hexutil.Big)(big.NewInt(7))
+				b.WithdrawalsHash = hash(1)
+			},
+			want:  london,
+			extra: []interface{}{*hash(1)},
+		},
+		{
+			name: "cancun",
+			fork: func(b *rpcBlock) {
+				b.BaseFee = (*hexutil.Big)(big.NewInt(7))
+				b.WithdrawalsHash = hash(1)
+				b.BlobGasUsed = uint64Ptr(131072)
+				b.ExcessBlobGas = uint64Ptr(0)
+				b.ParentBeaconRoot = hash(2)
+			},
+			want:  london,
+			extra: []interface{}{*hash(1), uint64(131072), uint64(0), *hash(2)},
+		},
+		{
+			name: "prague",
+			fork: func(b *rpcBlock) {
+				b.BaseFee = (*hexutil.Big)(big.NewInt(7))
+				b.WithdrawalsHash = hash(1)
+				b.BlobGasUsed = uint64Ptr(0)
+				b.ExcessBlobGas = uint64Ptr(393216)
+				b.ParentBeaconRoot = hash(2)
+				b.RequestsHash = hash(3)
+			},
+			want:  london,
+			extra: []interface{}{*hash(1), uint64(0), uint64(393216), *hash(2), *hash(3)},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			block := toRPCBlock(testHeader())
+			c.fork(block)
+
+			header, err := block.encodeHeader()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// go-ethereum in use knows fields up to london, later ones are checked to follow them in order
+			want, err := rlp.EncodeToBytes(c.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var wantFields, fields []rlp.RawValue
+			if err := rlp.DecodeBytes(want, &wantFields); err != nil {
+				t.Fatal(err)
+			}
+			if err := rlp.DecodeBytes(header, &fields); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, value := range c.extra {
+				field, err := rlp.EncodeToBytes(value)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				wantFields = append(wantFields, field)
+			}
+
+			if len(fields) != len(wantFields) {
+				t.Fatalf("header has %d fields, want %d", len(fields), len(wantFields))
+			}
+
+			for i := range fields {
+				if string(fields[i]) != string(wantFields[i]) {
+					t.Errorf("field %d = %x, want %x", i, fields[i], wantFields[i])
+				}
+			}
+
+			if c.extra == nil && crypto.Keccak256Hash(header) != c.want.Hash() {
+				t.Errorf("header hash = %s, want %s", crypto.Keccak256Hash(header), c.want.Hash())
+			}
+		})
+	}
+}
+
+func TestEncodeHeaderIncomplete(t *testing.T) {
+	block := toRPCBlock(testHeader())
+	block.Number = nil
+
+	if _, err := block.encodeHeader(); err == nil {
+		t.Error("header without number is encoded")
+	}
+}
+
+func testHeader() *types.Header {
+	return &types.Header{
+		ParentHash:  common.HexToHash("0x01"),
+		UncleHash:   types.EmptyUncleHash,
+		Coinbase:    common.HexToAddress("0x02"),
+		Root:        common.HexToHash("0x03"),
+		TxHash:      types.EmptyRootHash,
+		ReceiptHash: types.EmptyRootHash,
+		Difficulty:  big.NewInt(0),
+		Number:      big.NewInt(17034870),
+		GasLimit:    30000000,
+		GasUsed:     21000,
+		Time:        1681338455,
+		Extra:       []byte("builder"),
+		MixDigest:   common.HexToHash("0x04"),
+	}
+}
+
+func toRPCBlock(header *types.Header) *rpcBlock {
+	return &rpcBlock{
+		Hash:        header.Hash(),
+		ParentHash:  header.ParentHash,
+		UncleHash:   header.UncleHash,
+		Coinbase:    header.Coinbase,
+		Root:        header.Root,
+		TxHash:      header.TxHash,
+		ReceiptHash: header.ReceiptHash,
+		Bloom:       header.Bloom,
+		Difficulty:  (*hexutil.Big)(header.Difficulty),
+		Number:      (*hexutil.Big)(header.Number),
+		GasLimit:    hexutil.Uint64(header.GasLimit),
+		GasUsed:     hexutil.Uint64(header.GasUsed),
+		Time:        hexutil.Uint64(header.Time),
+		Extra:       header.Extra,
+		MixDigest:   header.MixDigest,
+		Nonce:       header.Nonce,
+		BaseFee:     (*hexutil.Big)(header.BaseFee),
+	}
+}
@@ -0,0 +1,174 @@
+package receiptproof
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// checkpointBatch is how many headers are requested at once while walking down from the checkpoint.
+const checkpointBatch = 100
+
+// HeaderSource tells the hash of the canonical block by its number.
+type HeaderSource interface {
+	CanonicalHash(ctx context.Context, number uint64) (common.Hash, error)
+}
+
+// RPCHeaders trusts an rpc other than the one receipts come from, e.g. an own node.
+type RPCHeaders struct {
+	client *rpc.Client
+}
+
+func NewRPCHeaders(client *rpc.Client) *RPCHeaders {
+	return &RPCHeaders{client: client}
+}
+
+func (h *RPCHeaders) CanonicalHash(ctx context.Context, number uint64) (common.Hash, error) {
+	var block *struct {
+		Hash common.Hash `json:"hash"`
+	}
+
+	if err := h.client.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.Uint64(number), false); err != nil {
+		return common.Hash{}, errors.Wrap(err, "failed to get header from the header source")
+	}
+
+	if block == nil {
+		return common.Hash{}, errors.From(errors.New("header source does not have the block"), logan.F{
+			"block": number,
+		})
+	}
+
+	return block.Hash, nil
+}
+
+// Checkpoint is a block hash trusted by the operator.
+type Checkpoint struct {
+	Number uint64      `fig:"number,required"`
+	Hash   common.Hash `fig:"hash,required"`
+}
+
+// CheckpointHeaders trusts nothing but the checkpoint. Hashes of earlier blocks are learnt by walking
+// parent hashes down from it, every header on the way is hashed locally, so the rpc can not forge
+// them. Blocks after the checkpoint can not be checked, as nothing links them to it yet.
+type CheckpointHeaders struct {
+	client      *rpc.Client
+	checkpoint  Checkpoint
+	maxDistance uint64
+
+	mu sync.Mutex
+	// hashes are the canonical hashes learnt so far, from the lowest block up to the checkpoint
+	hashes []common.Hash
+	lowest uint64
+}
+
+func NewCheckpointHeaders(client *rpc.Client, checkpoint Checkpoint, maxDistance uint64) *CheckpointHeaders {
+	return &CheckpointHeaders{
+		client:      client,
+		checkpoint:  checkpoint,
+		maxDistance: maxDistance,
+		hashes:      []common.Hash{checkpoint.Hash},
+		lowest:      checkpoint.Number,
+	}
+}
+
+func (h *CheckpointHeaders) CanonicalHash(ctx context.Context, number uint64) (common.Hash, error) {
+	if number > h.checkpoint.Number {
+		return common.Hash{}, errors.From(errors.New("block is after the checkpoint"), logan.F{
+			"block":      number,
+			"checkpoint": h.checkpoint.Number,
+		})
+	}
+
+	if h.checkpoint.Number-number > h.maxDistance {
+		return common.Hash{}, errors.From(errors.New("block is too far from the checkpoint"), logan.F{
+			"block":        number,
+			"checkpoint":   h.checkpoint.Number,
+			"max_distance": h.maxDistance,
+		})
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for h.lowest > number {
+		if err := h.walkDown(ctx, number); err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	return h.hashes[h.checkpoint.Number-number], nil
+}
+
+// walkDown learns hashes of a batch of blocks below the lowest known one. The parent hash of a header is
+// trusted only once the header hashes to the known hash of its block.
+func (h *CheckpointHeaders) walkDown(ctx context.Context, target uint64) error {
+	from := target
+	if h.lowest-from > checkpointBatch {
+		from = h.lowest - checkpointBatch
+	}
+
+	// blocks from the lowest known one down, the lowest known one gives the parent hash of the batch
+	blocks := make([]*rpcBlock, h.lowest-from+1)
+	batch := make([]rpc.BatchElem, len(blocks))
+
+	for i := range batch {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.Uint64(h.lowest - uint64(i)), false},
+			Result: &blocks[i],
+		}
+	}
+
+	if err := h.client.BatchCallContext(ctx, batch); err != nil {
+		return errors.Wrap(err, "failed to get headers")
+	}
+
+	// the hashes are kept only if the whole batch is checked
+	expected := h.hashes[len(h.hashes)-1]
+	learnt := make([]common.Hash, 0, len(blocks)-1)
+
+	for i, block := range blocks {
+		number := h.lowest - uint64(i)
+
+		if batch[i].Error != nil {
+			return errors.Wrap(batch[i].Error, "failed to get header", logan.F{
+				"block": number,
+			})
+		}
+
+		if block == nil {
+			return errors.From(errors.New("header not found"), logan.F{
+				"block": number,
+			})
+		}
+
+		header, err := block.encodeHeader()
+		if err != nil {
+			return errors.Wrap(err, "failed to encode header", logan.F{
+				"block": number,
+			})
+		}
+
+		if crypto.Keccak256Hash(header) != expected {
+			return errors.From(ErrInconsistent, logan.F{
+				"reason": "header does not hash to the canonical hash",
+				"block":  number,
+			})
+		}
+
+		expected = block.ParentHash
+		if number > from {
+			learnt = append(learnt, block.ParentHash)
+		}
+	}
+
+	h.hashes = append(h.hashes, learnt...)
+	h.lowest = from
+	return nil
+}
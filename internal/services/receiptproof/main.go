@@ -0,0 +1,296 @@
+package receiptproof
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+var (
+	// ErrInconsistent means the rpc returns data that does not match the block, nothing can be proven
+	ErrInconsistent = errors.New("rpc data is inconsistent with the block")
+	// ErrNotCanonical means the block is not the canonical one at its height by the header source
+	ErrNotCanonical = errors.New("block is not canonical")
+	// ErrNotIncluded means the transaction is not included in the block the rpc claims it to be in
+	ErrNotIncluded = errors.New("transaction is not included in the block")
+)
+
+// Prover proves transactions and their receipts are included in canonical blocks, so receipts are not
+// trusted just because the rpc returns them. Tries of the block are rebuilt from all of its
+// transactions and receipts and checked against the header, the header is checked by its hash and the
+// hash by the header source.
+type Prover struct {
+	network string
+	client  *rpc.Client
+	headers HeaderSource
+}
+
+func NewProver(network string, client *rpc.Client, headers HeaderSource) *Prover {
+	return &Prover{
+		network: network,
+		client:  client,
+		headers: headers,
+	}
+}
+
+// Prove returns the proof of the transaction the rpc claims to be included in the block.
+func (p *Prover) Prove(ctx context.Context, blockHash, txHash common.Hash) (*storage.ReceiptProof, error) {
+	block, header, err := getBlock(ctx, p.client, "eth_getBlockByHash", blockHash, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get deposit block")
+	}
+
+	number := block.Number.ToInt().Uint64()
+
+	canonical, err := p.headers.CanonicalHash(ctx, number)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get canonical block hash")
+	}
+
+	if canonical != blockHash {
+		return nil, errors.From(ErrNotCanonical, logan.F{
+			"block":          number,
+			"block_hash":     blockHash,
+			"canonical_hash": canonical,
+		})
+	}
+
+	txs, err := rawTransactions(ctx, p.client, block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get block transactions")
+	}
+
+	if root := types.DeriveSha(txs, trie.NewStackTrie(nil)); root != block.TxHash {
+		return nil, errors.From(ErrInconsistent, logan.F{
+			"reason": "transactions do not match the transactions root",
+			"block":  number,
+		})
+	}
+
+	index := -1
+	txHashes := make([]common.Hash, len(txs))
+
+	for i, tx := range txs {
+		txHashes[i] = crypto.Keccak256Hash(tx)
+		if txHashes[i] == txHash {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		return nil, errors.From(ErrNotIncluded, logan.F{
+			"block":   number,
+			"tx_hash": txHash,
+		})
+	}
+
+	receipts, err := blockReceipts(ctx, p.client, blockHash, txHashes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get block receipts")
+	}
+
+	encoded := make(rawList, len(receipts))
+	firstLogIndex := 0
+
+	for i, receipt := range receipts {
+		if encoded[i], err = encodeReceipt(receipt); err != nil {
+			return nil, err
+		}
+
+		if i < index {
+			firstLogIndex += len(receipt.Logs)
+		}
+	}
+
+	if root := types.DeriveSha(encoded, trie.NewStackTrie(nil)); root != block.ReceiptHash {
+		return nil, errors.From(ErrInconsistent, logan.F{
+			"reason": "receipts do not match the receipts root",
+			"block":  number,
+		})
+	}
+
+	txProof, err := prove(txs, index)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to prove transaction")
+	}
+
+	// receipts of the earlier transactions are proven too, so the first log index is proven by their logs
+	receiptProof, err := prove(encoded, upTo(index)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to prove receipt")
+	}
+
+	return &storage.ReceiptProof{
+		Network:       p.network,
+		TxHash:        txHash,
+		BlockNumber:   number,
+		BlockHash:     blockHash,
+		Header:        header,
+		TxIndex:       uint(index),
+		FirstLogIndex: uint(firstLogIndex),
+		TxProof:       txProof,
+		ReceiptProof:  receiptProof,
+		ProvenAt:      time.Now().UTC(),
+	}, nil
+}
+
+// prove returns trie nodes on the paths to the list elements, nodes shared by the paths are listed once.
+func prove(list rawList, indexes ...int) ([]hexutil.Bytes, error) {
+	tr := trie.NewEmpty(trie.NewDatabase(memorydb.New()))
+	for i, value := range list {
+		tr.Update(rlp.AppendUint64(nil, uint64(i)), value)
+	}
+
+	nodes := memorydb.New()
+	for _, index := range indexes {
+		if err := tr.Prove(rlp.AppendUint64(nil, uint64(index)), 0, nodes); err != nil {
+			return nil, err
+		}
+	}
+
+	iter := nodes.NewIterator(nil, nil)
+	defer iter.Release()
+
+	var result []hexutil.Bytes
+	for iter.Next() {
+		result = append(result, common.CopyBytes(iter.Value()))
+	}
+
+	return result, iter.Error()
+}
+
+// upTo returns indexes from zero up to the index inclusive.
+func upTo(index int) []int {
+	result := make([]int, index+1)
+	for i := range result {
+		result[i] = i
+	}
+
+	return result
+}
+
+// Verify checks the proof and returns the receipt it proves, with metadata of the block, the
+// transaction and logs set. Whether the block is canonical is up to the caller, only the header is
+// checked to hash to the block hash. FirstLogIndex is checked to be the number of logs of the earlier
+// receipts. Auditors check a vote of the oracle the same way.
+func Verify(proof storage.ReceiptProof) (*types.Receipt, error) {
+	if crypto.Keccak256Hash(proof.Header) != proof.BlockHash {
+		return nil, errors.New("header does not hash to the block hash")
+	}
+
+	fields, err := headerFields(proof.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := headerNumberOf(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if number != proof.BlockNumber {
+		return nil, errors.New("header number does not match the block number")
+	}
+
+	txRoot, err := headerHash(fields, headerTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptRoot, err := headerHash(fields, headerReceiptHash)
+	if err != nil {
+		return nil, err
+	}
+
+	key := rlp.AppendUint64(nil, uint64(proof.TxIndex))
+
+	tx, err := verifyProof(txRoot, key, proof.TxProof)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid transaction proof")
+	}
+
+	if crypto.Keccak256Hash(tx) != proof.TxHash {
+		return nil, errors.New("proven transaction does not hash to the transaction hash")
+	}
+
+	var firstLogIndex uint
+	for i := uint(0); i < proof.TxIndex; i++ {
+		raw, err := verifyProof(receiptRoot, rlp.AppendUint64(nil, uint64(i)), proof.ReceiptProof)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid receipt proof of an earlier transaction", logan.F{
+				"tx_index": i,
+			})
+		}
+
+		earlier, err := decodeReceipt(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		firstLogIndex += uint(len(earlier.Logs))
+	}
+
+	if firstLogIndex != proof.FirstLogIndex {
+		return nil, errors.From(errors.New("first log index does not match logs of the earlier receipts"), logan.F{
+			"first_log_index": proof.FirstLogIndex,
+			"proven":          firstLogIndex,
+		})
+	}
+
+	raw, err := verifyProof(receiptRoot, key, proof.ReceiptProof)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid receipt proof")
+	}
+
+	receipt, err := decodeReceipt(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt.TxHash = proof.TxHash
+	receipt.BlockHash = proof.BlockHash
+	receipt.BlockNumber = new(big.Int).SetUint64(proof.BlockNumber)
+	receipt.TransactionIndex = proof.TxIndex
+
+	for i, log := range receipt.Logs {
+		log.TxHash = proof.TxHash
+		log.TxIndex = proof.TxIndex
+		log.BlockHash = proof.BlockHash
+		log.BlockNumber = proof.BlockNumber
+		log.Index = firstLogIndex + uint(i)
+	}
+
+	return receipt, nil
+}
+
+func verifyProof(root common.Hash, key []byte, nodes []hexutil.Bytes) ([]byte, error) {
+	db := memorydb.New()
+	for _, node := range nodes {
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+
+	value, err := trie.VerifyProof(root, key, db)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return nil, errors.New("proof does not include the key")
+	}
+
+	return value, nil
+}
@@ -0,0 +1,259 @@
+package receiptproof
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+)
+
+// testBlock is a block of transactions of every type go-ethereum in use knows, its roots are derived by
+// go-ethereum independently of the prover.
+type testBlock struct {
+	header   *types.Header
+	txs      types.Transactions
+	receipts types.Receipts
+}
+
+func newTestBlock(t *testing.T) *testBlock {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainID := big.NewInt(1)
+	signer := types.LatestSignerForChainID(chainID)
+	to := common.HexToAddress("0x2b6ab0e7b4de7ba4a5c2b1b0b1ffb8a4ee4c9f7d")
+
+	unsigned := []types.TxData{
+		&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1e9), Gas: 50000, To: &to, Value: big.NewInt(1)},
+		&types.AccessListTx{ChainID: chainID, Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 50000, To: &to, AccessList: types.AccessList{{Address: to}}},
+		&types.DynamicFeeTx{ChainID: chainID, Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1e9), Gas: 90000, To: &to, Data: []byte{0xde, 0xad}},
+	}
+
+	logs := [][]*types.Log{
+		{{Address: to, Topics: []common.Hash{common.HexToHash("0x0a")}, Data: []byte{1}}},
+		// failed transactions have no logs
+		{},
+		{
+			{Address: to, Topics: []common.Hash{common.HexToHash("0x0b"), common.HexToHash("0x0c")}, Data: []byte{2}},
+			{Address: to, Topics: []common.Hash{}, Data: []byte{3}},
+		},
+	}
+
+	block := &testBlock{}
+	var gasUsed uint64
+
+	for i, data := range unsigned {
+		tx, err := types.SignNewTx(key, signer, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gasUsed += 30000
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: gasUsed,
+			Logs:              logs[i],
+		}
+		if len(logs[i]) == 0 {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		block.txs = append(block.txs, tx)
+		block.receipts = append(block.receipts, receipt)
+	}
+
+	block.header = testHeader()
+	block.header.BaseFee = big.NewInt(7)
+	block.header.GasUsed = gasUsed
+	block.header.TxHash = types.DeriveSha(block.txs, trie.NewStackTrie(nil))
+	block.header.ReceiptHash = types.DeriveSha(block.receipts, trie.NewStackTrie(nil))
+	block.header.Bloom = types.CreateBloom(block.receipts)
+
+	return block
+}
+
+func (b *testBlock) proof(t *testing.T, index int) storage.ReceiptProof {
+	header, err := rlp.EncodeToBytes(b.header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs := make(rawList, len(b.txs))
+	receipts := make(rawList, len(b.receipts))
+	firstLogIndex := 0
+
+	for i := range b.txs {
+		if txs[i], err = b.txs[i].MarshalBinary(); err != nil {
+			t.Fatal(err)
+		}
+
+		if receipts[i], err = b.receipts[i].MarshalBinary(); err != nil {
+			t.Fatal(err)
+		}
+
+		if i < index {
+			firstLogIndex += len(b.receipts[i].Logs)
+		}
+	}
+
+	txProof, err := prove(txs, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receiptProof, err := prove(receipts, upTo(index)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return storage.ReceiptProof{
+		Network:       "Ethereum",
+		TxHash:        b.txs[index].Hash(),
+		BlockNumber:   b.header.Number.Uint64(),
+		BlockHash:     b.header.Hash(),
+		Header:        header,
+		TxIndex:       uint(index),
+		FirstLogIndex: uint(firstLogIndex),
+		TxProof:       txProof,
+		ReceiptProof:  receiptProof,
+	}
+}
+
+func TestVerify(t *testing.T) {
+	block := newTestBlock(t)
+
+	cases := []struct {
+		name   string
+		index  int
+		tamper func(p *storage.ReceiptProof)
+		// wantErr is a part of the error, the proof is valid if it is empty
+		wantErr       string
+		wantFirstLog  uint
+		wantLogsCount int
+	}{
+		{name: "legacy transaction", index: 0, wantLogsCount: 1},
+		{name: "access list transaction", index: 1, wantFirstLog: 1},
+		{name: "dynamic fee transaction", index: 2, wantFirstLog: 1, wantLogsCount: 2},
+		{
+			name:    "first log index of another position",
+			index:   2,
+			tamper:  func(p *storage.ReceiptProof) { p.FirstLogIndex = 100 },
+			wantErr: "first log index does not match",
+		},
+		{
+			name:    "header of another block",
+			index:   0,
+			tamper:  func(p *storage.ReceiptProof) { p.BlockHash = common.HexToHash("0x01") },
+			wantErr: "header does not hash to the block hash",
+		},
+		{
+			name:    "another block number",
+			index:   0,
+			tamper:  func(p *storage.ReceiptProof) { p.BlockNumber++ },
+			wantErr: "header number does not match",
+		},
+		{
+			name:    "another transaction",
+			index:   1,
+			tamper:  func(p *storage.ReceiptProof) { p.TxHash = block.txs[0].Hash() },
+			wantErr: "proven transaction does not hash",
+		},
+		{
+			name:    "proofs of another index",
+			index:   1,
+			tamper:  func(p *storage.ReceiptProof) { p.TxIndex = 0 },
+			wantErr: "proof",
+		},
+		{
+			name:    "missing transaction proof nodes",
+			index:   2,
+			tamper:  func(p *storage.ReceiptProof) { p.TxProof = p.TxProof[:len(p.TxProof)-1] },
+			wantErr: "invalid transaction proof",
+		},
+		{
+			name:  "receipt proof of another transaction",
+			index: 1,
+			tamper: func(p *storage.ReceiptProof) {
+				p.ReceiptProof = block.proof(t, 0).ReceiptProof
+			},
+			wantErr: "invalid receipt proof",
+		},
+		{
+			name:  "missing earlier receipts",
+			index: 2,
+			tamper: func(p *storage.ReceiptProof) {
+				receipts := make(rawList, len(block.receipts))
+				for i, receipt := range block.receipts {
+					receipts[i], _ = receipt.MarshalBinary()
+				}
+
+				nodes, err := prove(receipts, 2)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				p.ReceiptProof = nodes
+			},
+			wantErr: "earlier transaction",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			proof := block.proof(t, c.index)
+			if c.tamper != nil {
+				c.tamper(&proof)
+			}
+
+			receipt, err := Verify(proof)
+			if c.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+					t.Fatalf("error = %v, want %q", err, c.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := block.receipts[c.index]
+			if receipt.Type != want.Type || receipt.Status != want.Status || receipt.CumulativeGasUsed != want.CumulativeGasUsed {
+				t.Errorf("receipt = %d/%d/%d, want %d/%d/%d", receipt.Type, receipt.Status, receipt.CumulativeGasUsed,
+					want.Type, want.Status, want.CumulativeGasUsed)
+			}
+
+			if receipt.TxHash != proof.TxHash || receipt.BlockHash != proof.BlockHash || receipt.TransactionIndex != uint(c.index) {
+				t.Errorf("receipt metadata = %s/%s/%d", receipt.TxHash, receipt.BlockHash, receipt.TransactionIndex)
+			}
+
+			if len(receipt.Logs) != c.wantLogsCount {
+				t.Fatalf("receipt has %d logs, want %d", len(receipt.Logs), c.wantLogsCount)
+			}
+
+			for i, log := range receipt.Logs {
+				if log.Index != c.wantFirstLog+uint(i) {
+					t.Errorf("log %d index = %d, want %d", i, log.Index, c.wantFirstLog+uint(i))
+				}
+
+				if log.Address != want.Logs[i].Address || string(log.Data) != string(want.Logs[i].Data) {
+					t.Errorf("log %d = %s/%x, want %s/%x", i, log.Address, log.Data, want.Logs[i].Address, want.Logs[i].Data)
+				}
+
+				if log.TxHash != proof.TxHash || log.BlockNumber != proof.BlockNumber {
+					t.Errorf("log %d metadata = %s/%d", i, log.TxHash, log.BlockNumber)
+				}
+			}
+		})
+	}
+}
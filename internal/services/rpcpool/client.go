@@ -0,0 +1,67 @@
+package rpcpool
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Client is the ethclient of the pool. Subscriptions need a websocket, so they are made directly to
+// the best websocket endpoint and end once it drops, it is up to the subscriber to subscribe again.
+type Client struct {
+	*ethclient.Client
+	pool *Pool
+}
+
+func (c *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return c.pool.subscribe(ctx, func(client *ethclient.Client) (ethereum.Subscription, error) {
+		return client.SubscribeFilterLogs(ctx, q, ch)
+	})
+}
+
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return c.pool.subscribe(ctx, func(client *ethclient.Client) (ethereum.Subscription, error) {
+		return client.SubscribeNewHead(ctx, ch)
+	})
+}
+
+// Pinned runs all requests of the function on a single endpoint whose head has reached the block, so
+// they see the same chain and no block up to the given one is missing. Endpoints behind the block are
+// skipped, the rest are tried in order until one of them succeeds.
+func (c *Client) Pinned(ctx context.Context, block uint64, request func(ctx context.Context, client *ethclient.Client) error) error {
+	return c.pool.pinned(ctx, block, func(ctx context.Context, conn *rpc.Client) error {
+		return request(ctx, ethclient.NewClient(conn))
+	})
+}
+
+// subscribe tries websocket endpoints in order until one of them accepts the subscription.
+func (p *Pool) subscribe(ctx context.Context, subscribe func(client *ethclient.Client) (ethereum.Subscription, error)) (ethereum.Subscription, error) {
+	var lastErr error = errors.New("no websocket rpc endpoints")
+
+	for _, e := range p.ordered() {
+		if !e.websocket {
+			continue
+		}
+
+		conn, err := e.dial(ctx)
+		if err == nil {
+			var sub ethereum.Subscription
+			if sub, err = subscribe(ethclient.NewClient(conn)); err == nil {
+				return sub, nil
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		p.failed(e, err)
+		lastErr = err
+	}
+
+	return nil, errors.Wrap(lastErr, "failed to subscribe")
+}
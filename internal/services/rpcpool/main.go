@@ -0,0 +1,387 @@
+package rpcpool
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+)
+
+// poolURL is never dialed, requests to it are served by the pool transport.
+const poolURL = "http://rpcpool"
+
+// Endpoint is an rpc of the network, endpoints with lower priority values are preferred.
+type Endpoint struct {
+	// Name labels the endpoint in logs and metrics instead of the url, which may contain an api key
+	Name     string `fig:"name"`
+	URL      string `fig:"url,required"`
+	Priority int    `fig:"priority"`
+}
+
+// Label is the name of the endpoint, the url host if it is not set.
+func (e Endpoint) Label() string {
+	if e.Name != "" {
+		return e.Name
+	}
+
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Host
+}
+
+// Opts tune health checks of endpoints.
+type Opts struct {
+	// ProbeInterval is how often heads of all endpoints are requested
+	ProbeInterval time.Duration `fig:"probe_interval"`
+	// Timeout bounds every request to an endpoint, subscriptions are not bounded
+	Timeout time.Duration `fig:"timeout"`
+	// MaxFailures is how many failures in a row make an endpoint unhealthy until it responds again
+	MaxFailures uint64 `fig:"max_failures"`
+	// MaxHeadLag is how many blocks an endpoint head may be behind the best one while it is healthy
+	MaxHeadLag uint64 `fig:"max_head_lag"`
+}
+
+// Pool spreads requests over endpoints of the network. Healthy endpoints go first by priority and then
+// by latency, unhealthy ones are used only if all healthy ones fail. Requests that must not be served by
+// an endpoint behind the chain are pinned to one that has reached the block. Errors the node answers with,
+// e.g. reverts or range limits, are returned as is, any other error fails over to the next endpoint.
+type Pool struct {
+	log       *logan.Entry
+	network   string
+	opts      Opts
+	endpoints []*endpoint
+
+	rpc    *rpc.Client
+	client *Client
+}
+
+// New creates the pool of the endpoints, they are probed in the background until the context is done.
+func New(ctx context.Context, log *logan.Entry, network string, endpoints []Endpoint, opts Opts) (*Pool, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no rpc endpoints")
+	}
+
+	if opts.ProbeInterval <= 0 || opts.Timeout <= 0 || opts.MaxFailures == 0 {
+		return nil, errors.New("probe interval, timeout and max failures must be positive")
+	}
+
+	p := &Pool{
+		log:       log,
+		network:   network,
+		opts:      opts,
+		endpoints: make([]*endpoint, 0, len(endpoints)),
+	}
+
+	names := make(map[string]struct{}, len(endpoints))
+
+	for _, e := range endpoints {
+		u, err := url.Parse(e.URL)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid rpc endpoint url", logan.F{
+				"endpoint": e.Name,
+			})
+		}
+
+		e.Name = e.Label()
+
+		if _, ok := names[e.Name]; ok {
+			return nil, errors.From(errors.New("duplicated rpc endpoint name, set names explicitly"), logan.F{
+				"endpoint": e.Name,
+			})
+		}
+
+		names[e.Name] = struct{}{}
+		p.endpoints = append(p.endpoints, &endpoint{
+			Endpoint:  e,
+			websocket: u.Scheme == "ws" || u.Scheme == "wss",
+		})
+	}
+
+	var err error
+
+	p.rpc, err = rpc.DialHTTPWithClient(poolURL, &http.Client{Transport: p})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create pool rpc client")
+	}
+
+	p.client = &Client{Client: ethclient.NewClient(p.rpc), pool: p}
+
+	go running.WithBackOff(ctx, log, "rpc-pool-probe", p.probe,
+		opts.ProbeInterval, opts.ProbeInterval, opts.ProbeInterval)
+
+	return p, nil
+}
+
+// RPC is the raw client of the pool, it does not support subscriptions.
+func (p *Pool) RPC() *rpc.Client {
+	return p.rpc
+}
+
+func (p *Pool) Client() *Client {
+	return p.client
+}
+
+// HasWebsocket reports whether subscriptions can be made.
+func (p *Pool) HasWebsocket() bool {
+	for _, e := range p.endpoints {
+		if e.websocket {
+			return true
+		}
+	}
+
+	return false
+}
+
+// do runs the request on endpoints in order until one of them responds.
+func (p *Pool) do(ctx context.Context, request func(ctx context.Context, conn *rpc.Client) error) error {
+	var lastErr error
+
+	for i, e := range p.ordered() {
+		if i > 0 {
+			failoversMetric.WithLabelValues(p.network).Inc()
+			p.log.WithError(lastErr).WithField("endpoint", e.Name).Debug("Failing over to the next rpc endpoint")
+		}
+
+		err := p.attempt(ctx, e, request)
+		if err == nil || isResponseError(err) {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		lastErr = err
+	}
+
+	return errors.Wrap(lastErr, "all rpc endpoints failed")
+}
+
+// pinned is do that runs the whole request on endpoints whose heads have reached the block. The head is
+// requested right before the request, as the one known from probes may be outdated.
+func (p *Pool) pinned(ctx context.Context, block uint64, request func(ctx context.Context, conn *rpc.Client) error) error {
+	var lastErr error = errors.New("no rpc endpoint has reached the block")
+
+	for _, e := range p.ordered() {
+		behind := false
+
+		err := p.attempt(ctx, e, func(ctx context.Context, conn *rpc.Client) error {
+			var head hexutil.Uint64
+			if err := conn.CallContext(ctx, &head, "eth_blockNumber"); err != nil {
+				return err
+			}
+
+			e.mu.Lock()
+			e.head = uint64(head)
+			e.mu.Unlock()
+
+			if uint64(head) < block {
+				behind = true
+				return nil
+			}
+
+			return request(ctx, conn)
+		})
+
+		if behind {
+			p.log.WithFields(logan.F{
+				"endpoint": e.Name,
+				"block":    block,
+			}).Debug("Skipping rpc endpoint behind the block")
+			continue
+		}
+
+		if err == nil || isResponseError(err) {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		failoversMetric.WithLabelValues(p.network).Inc()
+		lastErr = err
+	}
+
+	return errors.Wrap(lastErr, "all rpc endpoints failed", logan.F{
+		"block": block,
+	})
+}
+
+func (p *Pool) attempt(ctx context.Context, e *endpoint, request func(ctx context.Context, conn *rpc.Client) error) error {
+	attemptCtx, cancel := context.WithTimeout(ctx, p.opts.Timeout)
+	defer cancel()
+
+	conn, err := e.dial(attemptCtx)
+	if err == nil {
+		start := time.Now()
+
+		err = request(attemptCtx, conn)
+		if err == nil || isResponseError(err) {
+			p.succeeded(e, time.Since(start))
+			return err
+		}
+	}
+
+	// the caller giving up says nothing about the endpoint
+	if ctx.Err() != nil {
+		return err
+	}
+
+	p.failed(e, err)
+	return err
+}
+
+// ordered returns healthy endpoints by priority and latency followed by unhealthy ones.
+func (p *Pool) ordered() []*endpoint {
+	best := p.bestHead()
+
+	type ranked struct {
+		e       *endpoint
+		healthy bool
+		latency time.Duration
+	}
+
+	list := make([]ranked, len(p.endpoints))
+	for i, e := range p.endpoints {
+		healthy, latency := e.health(best, p.opts)
+		list[i] = ranked{e: e, healthy: healthy, latency: latency}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.healthy != b.healthy {
+			return a.healthy
+		}
+		if a.e.Priority != b.e.Priority {
+			return a.e.Priority < b.e.Priority
+		}
+
+		return a.latency < b.latency
+	})
+
+	result := make([]*endpoint, len(list))
+	for i, r := range list {
+		result[i] = r.e
+	}
+
+	return result
+}
+
+func (p *Pool) bestHead() uint64 {
+	var best uint64
+
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		if e.head > best {
+			best = e.head
+		}
+		e.mu.Unlock()
+	}
+
+	return best
+}
+
+func (p *Pool) succeeded(e *endpoint, took time.Duration) {
+	e.mu.Lock()
+	recovered := e.failures >= p.opts.MaxFailures
+	e.failures = 0
+	e.observe(took)
+	latency := e.latency
+	e.mu.Unlock()
+
+	latencyMetric.WithLabelValues(p.network, e.Name).Set(latency.Seconds())
+
+	if recovered {
+		p.log.WithField("endpoint", e.Name).Info("Rpc endpoint recovered")
+	}
+}
+
+func (p *Pool) failed(e *endpoint, err error) {
+	e.mu.Lock()
+	e.failures++
+	down := e.failures == p.opts.MaxFailures
+
+	// a websocket is dialed again on the next request, so a dropped connection is not reused
+	if e.websocket && e.conn != nil {
+		e.conn.Close()
+		e.conn = nil
+	}
+	e.mu.Unlock()
+
+	errorsMetric.WithLabelValues(p.network, e.Name).Inc()
+
+	if down {
+		p.log.WithError(err).WithField("endpoint", e.Name).Warn("Rpc endpoint is unhealthy")
+	}
+}
+
+// endpoint keeps the connection and health of an rpc.
+type endpoint struct {
+	Endpoint
+	websocket bool
+
+	mu       sync.Mutex
+	conn     *rpc.Client
+	failures uint64
+	latency  time.Duration
+	head     uint64
+}
+
+// dial connects lazily, so an endpoint being down at startup does not stop the service.
+func (e *endpoint) dial(ctx context.Context) (*rpc.Client, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		return e.conn, nil
+	}
+
+	conn, err := rpc.DialContext(ctx, e.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial rpc endpoint", logan.F{
+			"endpoint": e.Name,
+		})
+	}
+
+	e.conn = conn
+	return conn, nil
+}
+
+// health tells whether the endpoint responds and keeps up with the best head, an unknown head is not
+// considered stale.
+func (e *endpoint) health(best uint64, opts Opts) (bool, time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	fresh := e.head == 0 || best-e.head <= opts.MaxHeadLag
+	return e.failures < opts.MaxFailures && fresh, e.latency
+}
+
+// observe moves the exponential moving average of the latency, it has to be called under the lock.
+func (e *endpoint) observe(took time.Duration) {
+	if e.latency == 0 {
+		e.latency = took
+		return
+	}
+
+	e.latency = (e.latency*4 + took) / 5
+}
+
+// isResponseError reports whether the node has answered the request with an error.
+func isResponseError(err error) bool {
+	_, ok := err.(rpc.Error)
+	return ok
+}
@@ -0,0 +1,33 @@
+package rpcpool
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	errorsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_rpc_errors",
+		Help: "Number of failed requests to an rpc endpoint, errors the node answers with are not counted",
+	}, []string{"network", "endpoint"})
+
+	failoversMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "evm_rpc_failovers",
+		Help: "Number of requests retried on the next rpc endpoint",
+	}, []string{"network"})
+
+	latencyMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "evm_rpc_latency_seconds",
+		Help: "Moving average of rpc endpoint response time",
+	}, []string{"network", "endpoint"})
+
+	headLagMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "evm_rpc_head_lag",
+		Help: "Amount of blocks the rpc endpoint head is behind the best one",
+	}, []string{"network", "endpoint"})
+
+	healthyMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "evm_rpc_healthy",
+		Help: "Whether the rpc endpoint is used before the unhealthy ones",
+	}, []string{"network", "endpoint"})
+)
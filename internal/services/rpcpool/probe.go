@@ -0,0 +1,62 @@
+package rpcpool
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// probe requests heads of all endpoints, so unhealthy endpoints are noticed and recovered ones are used
+// again without waiting for requests to fail. Websocket endpoints dropped in the meantime are dialed
+// again by it as well.
+func (p *Pool) probe(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for _, e := range p.endpoints {
+		wg.Add(1)
+
+		go func(e *endpoint) {
+			defer wg.Done()
+
+			var head hexutil.Uint64
+
+			err := p.attempt(ctx, e, func(ctx context.Context, conn *rpc.Client) error {
+				return conn.CallContext(ctx, &head, "eth_blockNumber")
+			})
+			if err != nil {
+				return
+			}
+
+			e.mu.Lock()
+			e.head = uint64(head)
+			e.mu.Unlock()
+		}(e)
+	}
+
+	wg.Wait()
+
+	best := p.bestHead()
+
+	for _, e := range p.endpoints {
+		healthy, _ := e.health(best, p.opts)
+
+		e.mu.Lock()
+		lag := best - e.head
+		e.mu.Unlock()
+
+		headLagMetric.WithLabelValues(p.network, e.Name).Set(float64(lag))
+		healthyMetric.WithLabelValues(p.network, e.Name).Set(boolGauge(healthy))
+	}
+
+	return nil
+}
+
+func boolGauge(v bool) float64 {
+	if v {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,177 @@
+package rpcpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// codeInternal is the json-rpc code of the internal error.
+const codeInternal = -32603
+
+// message is a json-rpc request or response, the pool only passes params and results through.
+type message struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonError      `json:"error,omitempty"`
+}
+
+type jsonError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// RoundTrip serves requests of the pool rpc client, so everything built on top of the client goes
+// through the pool without knowing about it.
+func (p *Pool) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request")
+	}
+
+	var response interface{}
+
+	if batch := bytes.TrimLeft(body, " \t\r\n"); len(batch) > 0 && batch[0] == '[' {
+		var msgs []message
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal batch request")
+		}
+
+		response, err = p.batch(req.Context(), msgs)
+	} else {
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal request")
+		}
+
+		response, err = p.call(req.Context(), msg)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal response")
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(raw)),
+		ContentLength: int64(len(raw)),
+		Request:       req,
+	}, nil
+}
+
+func (p *Pool) call(ctx context.Context, msg message) (*message, error) {
+	args, err := params(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var result json.RawMessage
+
+	err = p.do(ctx, func(ctx context.Context, conn *rpc.Client) error {
+		return conn.CallContext(ctx, &result, msg.Method, args...)
+	})
+	if err != nil && !isResponseError(err) {
+		return nil, err
+	}
+
+	return respond(msg, result, err), nil
+}
+
+func (p *Pool) batch(ctx context.Context, msgs []message) ([]*message, error) {
+	results := make([]json.RawMessage, len(msgs))
+	elems := make([]rpc.BatchElem, len(msgs))
+
+	for i, msg := range msgs {
+		args, err := params(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		elems[i] = rpc.BatchElem{Method: msg.Method, Args: args, Result: &results[i]}
+	}
+
+	err := p.do(ctx, func(ctx context.Context, conn *rpc.Client) error {
+		// a failed attempt may have left some of the results
+		for i := range elems {
+			results[i] = nil
+			elems[i].Error = nil
+		}
+
+		return conn.BatchCallContext(ctx, elems)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*message, len(msgs))
+	for i, msg := range msgs {
+		responses[i] = respond(msg, results[i], elems[i].Error)
+	}
+
+	return responses, nil
+}
+
+// params passes raw params of the request as the call arguments.
+func params(msg message) ([]interface{}, error) {
+	if len(msg.Params) == 0 {
+		return nil, nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(msg.Params, &raw); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal request params")
+	}
+
+	args := make([]interface{}, len(raw))
+	for i := range raw {
+		args[i] = raw[i]
+	}
+
+	return args, nil
+}
+
+// respond turns the call outcome into the response. Errors the node answered with are passed as they
+// are, other errors of a batch element can only be the node response the element can not be decoded from.
+func respond(msg message, result json.RawMessage, err error) *message {
+	response := &message{Version: "2.0", ID: msg.ID}
+
+	switch e := err.(type) {
+	case nil:
+		// null is decoded as nothing, but an empty result is an error for the caller
+		if len(result) == 0 {
+			result = json.RawMessage("null")
+		}
+
+		response.Result = result
+	case rpc.Error:
+		response.Error = &jsonError{Code: e.ErrorCode(), Message: e.Error()}
+
+		if dataErr, ok := e.(rpc.DataError); ok {
+			response.Error.Data = dataErr.ErrorData()
+		}
+	default:
+		response.Error = &jsonError{Code: codeInternal, Message: err.Error()}
+	}
+
+	return response
+}
@@ -0,0 +1,89 @@
+package startblock
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type Client interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// ByTime returns the first block produced at or after the given time.
+func ByTime(ctx context.Context, client Client, at time.Time) (uint64, error) {
+	return search(ctx, client, func(ctx context.Context, block uint64) (bool, error) {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get header", logan.F{
+				"block": block,
+			})
+		}
+
+		return header.Time >= uint64(at.Unix()), nil
+	})
+}
+
+// ByDeployment returns the block the contract has been deployed in. It requires historical state, so
+// the node has to be an archive one.
+func ByDeployment(ctx context.Context, client Client, contract common.Address) (uint64, error) {
+	return search(ctx, client, func(ctx context.Context, block uint64) (bool, error) {
+		code, err := client.CodeAt(ctx, contract, new(big.Int).SetUint64(block))
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get contract code", logan.F{
+				"block": block,
+			})
+		}
+
+		return len(code) > 0, nil
+	})
+}
+
+// search binary-searches the first block satisfying the monotonic condition up to the head.
+func search(ctx context.Context, client Client, cond func(ctx context.Context, block uint64) (bool, error)) (uint64, error) {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get recent block")
+	}
+
+	ok, err := cond(ctx, head)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		return 0, errors.From(errors.New("no block satisfies the condition yet"), logan.F{
+			"head": head,
+		})
+	}
+
+	var searchErr error
+
+	found := sort.Search(int(head), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+
+		ok, err := cond(ctx, uint64(i))
+		if err != nil {
+			searchErr = err
+			return true
+		}
+
+		return ok
+	})
+
+	if searchErr != nil {
+		return 0, searchErr
+	}
+
+	return uint64(found), nil
+}
@@ -2,105 +2,82 @@ package voting
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 	"time"
 
-	events2 "github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	"github.com/rarimo/evm-saver-svc/internal/rarimo/events"
+	"github.com/rarimo/evm-saver-svc/internal/services/cachedeth"
+	"github.com/rarimo/evm-saver-svc/internal/services/finality"
+	"github.com/rarimo/evm-saver-svc/internal/services/policy"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
 	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
 	"gitlab.com/distributed_lab/running"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/gogo/protobuf/proto"
-	gobind "github.com/rarimo/evm-bridge-contracts/gobind/contracts/interfaces/handlers"
 	"github.com/rarimo/evm-saver-svc/internal/config"
 	"github.com/rarimo/evm-saver-svc/internal/rarimo"
 	rarimocore "github.com/rarimo/rarimo-core/x/rarimocore/types"
 	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
-	"github.com/rarimo/saver-grpc-lib/voter"
 	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
-const (
-	IERC20DepositedTopic   = "0x043d52f9acdd847f0210803c386559db9e09d492143f2072fe30ea62ff0bb639"
-	IERC721DepositedTopic  = "0x7f787dd0c844dac4f8bfc4044046cdab3be531f7eefa9b740c531e48a99725e1"
-	IERC1155DepositedTopic = "0x103b790f2fa3a8676ff87c3620a55f0853d0e45128a8c7e9fadf29e17c51d07a"
-	INativeDepositedTopic  = "0x9a47c8733424880a9e86a368eff95da5e7d36b68474a95eb097be2e43c116f27"
-
-	EventNameTopic = 0
-)
-
 type ReceiptsProvider interface {
 	GetTxReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
 }
 
-type IERC20Parser interface {
-	ParseDepositedERC20(log types.Log) (*gobind.IERC20HandlerDepositedERC20, error)
-}
-
-type INativeParser interface {
-	ParseDepositedNative(log types.Log) (*gobind.INativeHandlerDepositedNative, error)
-}
-
 type EvmTransferVerifier struct {
 	log       *logan.Entry
 	homeChain string
 
 	receiptsProvider ReceiptsProvider
-	parser20         IERC20Parser
-	parserNative     INativeParser
+	contracts        config.Contracts
+	finality         *finality.Source
+	proofs           *ProofKeeper
+	quorum           *quorum
+	policy           *policy.Policy
+	senders          policy.SenderProvider
 
 	oracleQueryClient oracletypes.QueryClient
 	tokenQueryClient  tokentypes.QueryClient
 	msger             *rarimo.MessageMaker
 }
 
-func RunVoter(ctx context.Context, cfg config.Config) {
-	v := voter.NewVoter(cfg.Ethereum().NetworkName, cfg.Log(), cfg.Broadcaster(), map[rarimocore.OpType]voter.Verifier{
-		rarimocore.OpType_TRANSFER: verifiers.NewTransferVerifier(
-			NewTransfersVerifier(cfg),
-			cfg.Log().WithField("who", "evm-transfer-verifier"),
-		),
-	})
+// RunVoter votes for transfers from all configured networks. Each transfer is verified by the verifier
+// of the network it comes from.
+func RunVoter(ctx context.Context, cfg config.Config, opts RouterOpts) {
+	router := NewRouter(cfg, opts)
 
-	// catchup tends to panic on startup and doesn't handle it by itself, so we wrap it into retry loop
-	running.UntilSuccess(ctx, cfg.Log(), "voter-catchup", func(ctx context.Context) (bool, error) {
-		voter.
-			NewCatchupper(cfg.Cosmos(), v, cfg.Log()).
-			Run(ctx)
+	go router.RunPostponed(ctx)
 
-		return true, nil
+	running.UntilSuccess(ctx, cfg.Log(), "voter-catchup", func(ctx context.Context) (bool, error) {
+		err := newCatchupper(cfg, router).run(ctx)
+		return err == nil, err
 	}, 1*time.Second, 5*time.Second)
 
 	// run blocking verification subscription
-	voter.
-		NewTransferSubscriber(v, cfg.Tendermint(), cfg.Cosmos(), cfg.Log(), cfg.Subscriber()).
-		Run(ctx)
+	newSubscriber(cfg, router).run(ctx)
 }
 
-func NewTransfersVerifier(cfg config.Config) *EvmTransferVerifier {
-	erc20Filterer, err := gobind.NewIERC20HandlerFilterer(cfg.Ethereum().ContractAddr, cfg.Ethereum().RPCClient)
-	if err != nil {
-		panic(errors.Wrap(err, "failed to init erc20 filterer"))
-	}
-
-	nativeFilterer, err := gobind.NewINativeHandlerFilterer(cfg.Ethereum().ContractAddr, cfg.Ethereum().RPCClient)
-	if err != nil {
-		panic(errors.Wrap(err, "failed to init native filterer"))
-	}
+func NewTransfersVerifier(cfg config.Config, network *config.Ethereum, txs *cachedeth.Provider, proofs *storage.Proofs) *EvmTransferVerifier {
+	log := cfg.Log().WithField("network", network.NetworkName)
 
 	return &EvmTransferVerifier{
-		log:               cfg.Log(),
-		homeChain:         cfg.Ethereum().NetworkName,
+		log:               log,
+		homeChain:         network.NetworkName,
 		oracleQueryClient: oracletypes.NewQueryClient(cfg.Cosmos()),
 		tokenQueryClient:  tokentypes.NewQueryClient(cfg.Cosmos()),
-		receiptsProvider:  cfg.Ethereum().TxProvider,
-		parser20:          erc20Filterer,
-		parserNative:      nativeFilterer,
-		msger:             rarimo.NewMessageMaker(cfg),
+		receiptsProvider:  txs,
+		proofs:            NewProofKeeper(cfg, network, txs, proofs),
+		contracts:         network.Contracts,
+		senders:           txs,
+		finality:          network.Finality,
+		quorum:            newQuorum(log, network),
+		policy:            cfg.Policy(),
+		msger:             rarimo.NewMessageMaker(cfg, network),
 	}
 }
 
@@ -116,6 +93,28 @@ func (e *EvmTransferVerifier) VerifyTransfer(ctx context.Context, txHash, eventI
 		})
 	}
 
+	// the vote is postponed, as the deposit may still become final or be dropped by a reorg
+	block := txReceipt.BlockNumber.Uint64()
+	if err := e.finality.EnsureFinal(ctx, block); err != nil {
+		if errors.Cause(err) == finality.ErrNotFinal {
+			err = &notFinalError{block: block}
+		}
+
+		return errors.Wrap(err, "deposit block is not final", logan.F{
+			"tx_hash": txHash,
+		})
+	}
+
+	// the vote is not cast at all if the receipt can not be proven, so other oracles decide on the transfer
+	if e.proofs != nil {
+		txReceipt, err = e.proofs.Receipt(ctx, common.HexToHash(txHash))
+		if err != nil {
+			return errors.Wrap(err, "failed to prove deposit receipt", logan.F{
+				"tx_hash": txHash,
+			})
+		}
+	}
+
 	logID, err := strconv.Atoi(eventId)
 	if err != nil {
 		return errors.Wrap(err, "failed to parse event id")
@@ -139,37 +138,51 @@ func (e *EvmTransferVerifier) VerifyTransfer(ctx context.Context, txHash, eventI
 		})
 	}
 
-	switch eventLog.Topics[EventNameTopic].Hex() { // I wish abigen could generate generic code
-	case IERC20DepositedTopic:
-		event, err := e.parser20.ParseDepositedERC20(*eventLog)
-		if err != nil {
-			return errors.Wrap(verifiers.ErrWrongOperationContent, "failed to parse erc20 log")
-		}
+	// logs of the same layout can be emitted by any contract, only the bridge active at the block counts
+	if !e.contracts.Accepts(*eventLog) {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "log is not emitted by the active bridge contract", logan.F{
+			"tx_hash":  txHash,
+			"contract": eventLog.Address,
+		})
+	}
 
-		msg, err := e.msger.TransferMsg(ctx, &events2.IERC20Event{E: event})
-		if err != nil {
-			return errors.Wrap(err, "failed to make transfer msg")
+	// the log is confirmed before it decides the vote, as the rpc it comes from may lie
+	if e.quorum != nil {
+		if err := e.quorum.check(ctx, eventLog); err != nil {
+			return errors.Wrap(err, "failed to confirm deposit log by the quorum", logan.F{
+				"tx_hash": txHash,
+			})
 		}
+	}
 
-		return e.checkTransferAtCore(ctx, msg, transfer)
-	case INativeDepositedTopic: // hack for making native contract distinguishable
-		event, err := e.parserNative.ParseDepositedNative(*eventLog)
-		if err != nil {
-			return errors.Wrap(verifiers.ErrWrongOperationContent, "failed to parse erc20 log")
-		}
+	// Accepts has checked the topic belongs to the event set of the contract version, so the log is
+	// decoded the same way the saver has decoded it
+	event, err := events.Decode(*eventLog)
+	if err != nil {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "failed to decode deposit log", logan.F{
+			"tx_hash": txHash,
+			"reason":  err.Error(),
+		})
+	}
 
-		msg, err := e.msger.TransferMsg(ctx, &events2.INativeEvent{E: event})
-		if err != nil {
-			return errors.Wrap(err, "failed to make transfer msg")
-		}
-		if err != nil {
-			return errors.Wrap(err, "failed to make transfer msg")
-		}
+	// the vote is not cast at all, so other oracles decide on the transfer
+	rejection, err := e.policy.Check(ctx, event, e.senders)
+	if err != nil {
+		return errors.Wrap(err, "failed to check deposit policy")
+	}
 
-		return e.checkTransferAtCore(ctx, msg, transfer)
-	default:
-		return fmt.Errorf("unsupported topic: %s", eventLog.Topics[0].Hex())
+	if rejection != nil {
+		return errors.Wrap(rejection, "deposit is rejected by the policy", logan.F{
+			"tx_hash": txHash,
+		})
+	}
+
+	msg, err := e.msger.TransferMsg(ctx, event)
+	if err != nil {
+		return errors.Wrap(err, "failed to make transfer msg")
 	}
+
+	return e.checkTransferAtCore(ctx, msg, transfer)
 }
 
 func (e *EvmTransferVerifier) checkTransferAtCore(ctx context.Context,
@@ -0,0 +1,16 @@
+package voting
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var quorumDisagreementsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "evm_quorum_disagreements",
+	Help: "Number of deposit logs a quorum provider returned differently than the main rpc",
+}, []string{"network", "provider"})
+
+var receiptProofAlertsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "evm_receipt_proof_alerts",
+	Help: "Number of deposit receipts the rpc returned that could not be proven against canonical headers",
+}, []string{"network"})
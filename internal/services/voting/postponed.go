@@ -0,0 +1,151 @@
+package voting
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rarimo/evm-saver-svc/internal/services/finality"
+	rarimocore "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	"github.com/rarimo/saver-grpc-lib/voter"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// notFinalError postpones the vote until the deposit block is final.
+type notFinalError struct {
+	block uint64
+}
+
+func (e *notFinalError) Error() string {
+	return fmt.Sprintf("block %d is not final yet", e.block)
+}
+
+// postponed keeps operations the voter could not vote on because their deposit blocks were not final,
+// as neither the subscriber nor the catch-up returns to an operation. Operations are kept only while
+// the router processes them again, so the api router does not pile them up.
+type postponed struct {
+	mu sync.Mutex
+	// ops are postponed operations by index, nil until the router runs
+	ops map[string]postponedOp
+}
+
+type postponedOp struct {
+	operation rarimocore.Operation
+	block     uint64
+	finality  *finality.Source
+}
+
+func (p *postponed) add(operation rarimocore.Operation, block uint64, source *finality.Source) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ops != nil {
+		p.ops[operation.Index] = postponedOp{operation: operation, block: block, finality: source}
+	}
+}
+
+func (p *postponed) start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ops == nil {
+		p.ops = make(map[string]postponedOp)
+	}
+}
+
+// takeFinal removes and returns operations with final deposit blocks. The last final block is requested
+// once per network.
+func (p *postponed) takeFinal(ctx context.Context) ([]postponedOp, error) {
+	p.mu.Lock()
+	ops := make([]postponedOp, 0, len(p.ops))
+	for _, op := range p.ops {
+		ops = append(ops, op)
+	}
+	p.mu.Unlock()
+
+	lastFinal := make(map[*finality.Source]uint64)
+	var result []postponedOp
+
+	for _, op := range ops {
+		last, ok := lastFinal[op.finality]
+		if !ok {
+			block, isFinal, err := op.finality.LastFinal(ctx)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get last final block")
+			}
+
+			if !isFinal {
+				continue
+			}
+
+			last = block
+			lastFinal[op.finality] = last
+		}
+
+		if op.block > last {
+			continue
+		}
+
+		p.mu.Lock()
+		delete(p.ops, op.operation.Index)
+		p.mu.Unlock()
+
+		result = append(result, op)
+	}
+
+	return result, nil
+}
+
+// postponingVerifier postpones operations the verifier can not vote on until their deposit blocks are
+// final.
+type postponingVerifier struct {
+	voter.Verifier
+	finality  *finality.Source
+	postponed *postponed
+}
+
+func (v postponingVerifier) Verify(ctx context.Context, operation rarimocore.Operation) (rarimocore.VoteType, error) {
+	result, err := v.Verifier.Verify(ctx, operation)
+	if notFinal, ok := errors.Cause(err).(*notFinalError); ok {
+		v.postponed.add(operation, notFinal.block, v.finality)
+	}
+
+	return result, err
+}
+
+// processPostponed processes again postponed operations whose deposit blocks have become final, unless
+// they have been finished without the vote meanwhile.
+func (r *Router) processPostponed(ctx context.Context) error {
+	ops, err := r.postponed.takeFinal(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, op := range ops {
+		log := r.log.WithField("index", op.operation.Index)
+
+		resp, err := r.core.Operation(ctx, &rarimocore.QueryGetOperationRequest{Index: op.operation.Index})
+		if err != nil {
+			for _, rest := range ops[i:] {
+				r.postponed.add(rest.operation, rest.block, rest.finality)
+			}
+
+			return errors.Wrap(err, "failed to fetch postponed operation", logan.F{
+				"index": op.operation.Index,
+			})
+		}
+
+		if resp.Operation.Status != rarimocore.OpStatus_INITIALIZED {
+			continue
+		}
+
+		log.Info("Processing postponed operation")
+
+		if err := r.Process(ctx, resp.Operation); err != nil {
+			log.WithError(err).Error("failed to process postponed operation")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package voting
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/evm-saver-svc/internal/services/finality"
+	"github.com/rarimo/evm-saver-svc/internal/services/receiptproof"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// ProofKeeper proves deposit receipts and keeps the proofs, so auditors can check votes later. Proofs
+// are deterministic, so without the storage they are proven again on every request.
+type ProofKeeper struct {
+	log      *logan.Entry
+	network  string
+	receipts ReceiptsProvider
+	finality *finality.Source
+	prover   *receiptproof.Prover
+	// proofs is nil if the process does not own the storage
+	proofs *storage.Proofs
+}
+
+// NewProofKeeper returns nil if receipt proofs are not configured for the network.
+func NewProofKeeper(cfg config.Config, network *config.Ethereum, receipts ReceiptsProvider, proofs *storage.Proofs) *ProofKeeper {
+	if network.Prover == nil {
+		return nil
+	}
+
+	return &ProofKeeper{
+		log:      cfg.Log().WithField("network", network.NetworkName),
+		network:  network.NetworkName,
+		receipts: receipts,
+		finality: network.Finality,
+		prover:   network.Prover,
+		proofs:   proofs,
+	}
+}
+
+// Get returns the kept proof of the transaction, the transaction is proven first if there is none.
+// Only transactions of final blocks are proven.
+func (k *ProofKeeper) Get(ctx context.Context, txHash common.Hash) (*storage.ReceiptProof, error) {
+	if k.proofs != nil {
+		proof, err := k.proofs.Get(k.network, txHash)
+		if err != nil {
+			return nil, err
+		}
+
+		if proof != nil {
+			return proof, nil
+		}
+	}
+
+	// the receipt only tells the block to prove the transaction in
+	receipt, err := k.receipts.GetTxReceipt(ctx, txHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transaction")
+	}
+
+	if err := k.finality.EnsureFinal(ctx, receipt.BlockNumber.Uint64()); err != nil {
+		return nil, errors.Wrap(err, "deposit block is not final")
+	}
+
+	proof, err := k.prover.Prove(ctx, receipt.BlockHash, txHash)
+	if err != nil {
+		switch errors.Cause(err) {
+		case receiptproof.ErrInconsistent, receiptproof.ErrNotCanonical, receiptproof.ErrNotIncluded:
+			receiptProofAlertsMetric.WithLabelValues(k.network).Inc()
+			k.log.WithError(err).WithFields(logan.F{
+				"tx_hash":    txHash,
+				"block_hash": receipt.BlockHash,
+			}).Error("SECURITY ALERT: deposit receipt can not be proven against canonical headers")
+		}
+
+		return nil, errors.Wrap(err, "failed to prove deposit receipt")
+	}
+
+	if k.proofs != nil {
+		if err := k.proofs.Put(*proof); err != nil {
+			return nil, err
+		}
+	}
+
+	return proof, nil
+}
+
+// Receipt returns the receipt taken from its proof, so nothing the rpc says is trusted unproven.
+func (k *ProofKeeper) Receipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	proof, err := k.Get(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := receiptproof.Verify(*proof)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid receipt proof")
+	}
+
+	return receipt, nil
+}
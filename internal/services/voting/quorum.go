@@ -0,0 +1,153 @@
+package voting
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// quorum checks deposit logs with independent providers, so a single compromised or faulty rpc can not
+// make the oracle vote for a deposit that does not exist.
+type quorum struct {
+	log       *logan.Entry
+	network   string
+	providers []config.QuorumProvider
+	minAgree  int
+}
+
+type quorumVote struct {
+	provider string
+	// mismatch describes how the provider disagrees, it is empty if the provider agrees
+	mismatch string
+	err      error
+}
+
+func newQuorum(log *logan.Entry, network *config.Ethereum) *quorum {
+	if len(network.QuorumProviders) == 0 {
+		return nil
+	}
+
+	return &quorum{
+		log:       log,
+		network:   network.NetworkName,
+		providers: network.QuorumProviders,
+		minAgree:  int(network.Quorum.MinAgree),
+	}
+}
+
+// check requests the deposit log from every provider. It returns ErrWrongOperationContent only if the
+// quorum of providers contradicts the log, any other miss of the quorum returns an error, so the vote is
+// not cast and a single faulty provider can not make the oracle vote against a deposit. Every
+// disagreement is reported as a security alert, even if the quorum is reached.
+func (q *quorum) check(ctx context.Context, expected *types.Log) error {
+	votes := make([]quorumVote, len(q.providers))
+
+	var wg sync.WaitGroup
+
+	for i, provider := range q.providers {
+		wg.Add(1)
+
+		go func(i int, provider config.QuorumProvider) {
+			defer wg.Done()
+
+			mismatch, err := compareLog(ctx, provider, expected)
+			votes[i] = quorumVote{provider: provider.Name, mismatch: mismatch, err: err}
+		}(i, provider)
+	}
+
+	wg.Wait()
+
+	agreed, disagreed := 0, 0
+	lastErr := errors.New("deposit log is not confirmed by the quorum")
+
+	for _, vote := range votes {
+		switch {
+		case vote.err != nil:
+			lastErr = vote.err
+			q.log.WithError(vote.err).WithField("provider", vote.provider).Warn("Quorum provider failed to return the deposit log")
+		case vote.mismatch != "":
+			disagreed++
+			quorumDisagreementsMetric.WithLabelValues(q.network, vote.provider).Inc()
+			q.log.WithFields(logan.F{
+				"provider":  vote.provider,
+				"mismatch":  vote.mismatch,
+				"tx_hash":   expected.TxHash,
+				"log_index": expected.Index,
+				"block":     expected.BlockNumber,
+			}).Error("SECURITY ALERT: rpc providers disagree on the deposit log")
+		default:
+			agreed++
+		}
+	}
+
+	if agreed >= q.minAgree {
+		return nil
+	}
+
+	fields := logan.F{
+		"agreed":    agreed,
+		"disagreed": disagreed,
+		"min_agree": q.minAgree,
+	}
+
+	if disagreed >= q.minAgree {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "deposit log is contradicted by the quorum", fields)
+	}
+
+	return errors.Wrap(lastErr, "deposit log is not confirmed by the quorum", fields)
+}
+
+// compareLog returns what differs in the log the provider has. A provider that does not have the
+// transaction can not tell anything, as it may be behind the deposit block or not index old
+// transactions, so it fails instead of disagreeing.
+func compareLog(ctx context.Context, provider config.QuorumProvider, expected *types.Log) (string, error) {
+	receipt, err := provider.Client.TransactionReceipt(ctx, expected.TxHash)
+	if err == ethereum.NotFound {
+		return "", errors.New("provider does not have the transaction")
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get tx receipt")
+	}
+
+	for _, log := range receipt.Logs {
+		if log.Index != expected.Index {
+			continue
+		}
+
+		switch {
+		case log.BlockHash != expected.BlockHash || log.BlockNumber != expected.BlockNumber:
+			return "block", nil
+		case log.Address != expected.Address:
+			return "address", nil
+		case !equalTopics(log, expected):
+			return "topics", nil
+		case !bytes.Equal(log.Data, expected.Data):
+			return "data", nil
+		default:
+			return "", nil
+		}
+	}
+
+	return "log not found", nil
+}
+
+func equalTopics(a, b *types.Log) bool {
+	if len(a.Topics) != len(b.Topics) {
+		return false
+	}
+
+	for i := range a.Topics {
+		if a.Topics[i] != b.Topics[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,113 @@
+package voting
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	"github.com/rarimo/evm-saver-svc/internal/services/cachedeth"
+	"github.com/rarimo/evm-saver-svc/internal/storage"
+	rarimocore "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	"github.com/rarimo/saver-grpc-lib/voter"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+)
+
+// Router passes transfer operations to the voter of the network the transfer comes from. Votes are
+// indexed by the source chain, so every network has its own voter.
+type Router struct {
+	log       *logan.Entry
+	sender    string
+	voters    map[string]*voter.Voter
+	core      rarimocore.QueryClient
+	postponed *postponed
+}
+
+// RouterOpts are stores of the process voters may use, the ones that are nil are not used.
+type RouterOpts struct {
+	// TxStore keeps transactions and receipts voters get
+	TxStore *cachedeth.Store
+	// Proofs keep receipt proofs, without them receipts are proven again on every vote
+	Proofs *storage.Proofs
+}
+
+func NewRouter(cfg config.Config, opts RouterOpts) *Router {
+	r := &Router{
+		log:       cfg.Log().WithField("who", "evm-voter-router"),
+		sender:    cfg.Broadcaster().Sender(),
+		voters:    make(map[string]*voter.Voter),
+		core:      rarimocore.NewQueryClient(cfg.Cosmos()),
+		postponed: new(postponed),
+	}
+
+	for _, network := range cfg.Networks() {
+		log := cfg.Log().WithFields(logan.F{
+			"who":     "evm-saver-voter",
+			"network": network.NetworkName,
+		})
+
+		txs := network.TxProvider
+		if opts.TxStore != nil {
+			var err error
+			if txs, err = txs.WithStore(opts.TxStore); err != nil {
+				panic(errors.Wrap(err, "failed to init tx provider", logan.F{
+					"network": network.NetworkName,
+				}))
+			}
+		}
+
+		r.voters[network.NetworkName] = voter.NewVoter(network.NetworkName, log, cfg.Broadcaster(), map[rarimocore.OpType]voter.Verifier{
+			rarimocore.OpType_TRANSFER: postponingVerifier{
+				Verifier:  verifiers.NewTransferVerifier(NewTransfersVerifier(cfg, network, txs, opts.Proofs), log),
+				finality:  network.Finality,
+				postponed: r.postponed,
+			},
+		})
+	}
+
+	return r
+}
+
+// Process votes for the operation if it is a transfer from one of the configured networks, other
+// operations are skipped.
+func (r *Router) Process(ctx context.Context, operation rarimocore.Operation) error {
+	log := r.log.WithField("index", operation.Index)
+
+	if operation.OperationType != rarimocore.OpType_TRANSFER {
+		log.Debugf("Skipping operation of type %s", operation.OperationType.String())
+		return nil
+	}
+
+	transfer := new(rarimocore.Transfer)
+	if err := proto.Unmarshal(operation.Details.Value, transfer); err != nil {
+		return errors.Wrap(err, "failed to unmarshal transfer", logan.F{
+			"index": operation.Index,
+		})
+	}
+
+	v, ok := r.voters[transfer.From.Chain]
+	if !ok {
+		log.WithField("chain", transfer.From.Chain).Debug("Skipping transfer from unsupported network")
+		return nil
+	}
+
+	return v.Process(ctx, operation)
+}
+
+// RunPostponed processes operations postponed until their deposit blocks are final, the router keeps
+// postponed operations only while it runs.
+func (r *Router) RunPostponed(ctx context.Context) {
+	r.postponed.start()
+
+	running.WithBackOff(ctx, r.log.WithField("who", "voter-postponed"), "voter-postponed",
+		r.processPostponed,
+		5*time.Second, 5*time.Second, time.Minute)
+}
+
+// Sender is the account votes are cast from, it is the same for all networks.
+func (r *Router) Sender() string {
+	return r.sender
+}
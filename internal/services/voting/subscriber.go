@@ -0,0 +1,149 @@
+package voting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/rarimo/evm-saver-svc/internal/config"
+	rarimocore "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	"github.com/rarimo/saver-grpc-lib/voter"
+	"github.com/tendermint/tendermint/rpc/client/http"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+)
+
+// subscriber passes new transfer operations to the router. It does the same as the subscriber of
+// saver-grpc-lib, which is bound to a single voter.
+type subscriber struct {
+	log    *logan.Entry
+	router *Router
+	client *http.HTTP
+	core   rarimocore.QueryClient
+	cfg    voter.SubscriberConfig
+}
+
+func newSubscriber(cfg config.Config, router *Router) *subscriber {
+	return &subscriber{
+		log:    cfg.Log().WithField("who", "voter-subscriber"),
+		router: router,
+		client: cfg.Tendermint(),
+		core:   rarimocore.NewQueryClient(cfg.Cosmos()),
+		cfg:    cfg.Subscriber(),
+	}
+}
+
+func (s *subscriber) run(ctx context.Context) {
+	running.WithBackOff(ctx, s.log, "voter-subscriber",
+		s.runOnce,
+		s.cfg.MinRetryPeriod, s.cfg.MinRetryPeriod, s.cfg.MaxRetryPeriod)
+}
+
+func (s *subscriber) runOnce(ctx context.Context) error {
+	s.log.Info("Starting subscription for the new unvoted operations")
+
+	out, err := s.client.Subscribe(ctx, voter.OpServiceName, voter.OpQueryTransfer, voter.OpPoolSize)
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe to the new operations")
+	}
+
+	defer func() {
+		if err := s.client.Unsubscribe(context.Background(), voter.OpServiceName, voter.OpQueryTransfer); err != nil {
+			s.log.WithError(err).Warn("failed to unsubscribe from the new operations")
+		}
+	}()
+
+	key := fmt.Sprintf("%s.%s", rarimocore.EventTypeNewOperation, rarimocore.AttributeKeyOperationId)
+
+	for {
+		var indexes []string
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-out:
+			if !ok {
+				return errors.New("operations subscription is closed")
+			}
+
+			indexes = event.Events[key]
+		}
+
+		for _, index := range indexes {
+			log := s.log.WithField("index", index)
+			log.Info("New operation found")
+
+			resp, err := s.core.Operation(ctx, &rarimocore.QueryGetOperationRequest{Index: index})
+			if err != nil {
+				log.WithError(err).Error("failed to fetch operation data")
+				continue
+			}
+
+			if resp.Operation.Status != rarimocore.OpStatus_INITIALIZED {
+				continue
+			}
+
+			if err := s.router.Process(ctx, resp.Operation); err != nil {
+				log.WithError(err).Error("failed to process operation")
+			}
+		}
+	}
+}
+
+// catchupper votes for operations created while the voter was down.
+type catchupper struct {
+	log    *logan.Entry
+	router *Router
+	core   rarimocore.QueryClient
+}
+
+func newCatchupper(cfg config.Config, router *Router) *catchupper {
+	return &catchupper{
+		log:    cfg.Log().WithField("who", "voter-catchup"),
+		router: router,
+		core:   rarimocore.NewQueryClient(cfg.Cosmos()),
+	}
+}
+
+func (c *catchupper) run(ctx context.Context) error {
+	c.log.Info("Starting catchup unvoted operations")
+
+	var nextKey []byte
+
+	for {
+		resp, err := c.core.OperationAll(ctx, &rarimocore.QueryAllOperationRequest{
+			Pagination: &query.PageRequest{Key: nextKey},
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to get operations")
+		}
+
+		for _, op := range resp.Operation {
+			if op.Status != rarimocore.OpStatus_INITIALIZED {
+				continue
+			}
+
+			log := c.log.WithField("index", op.Index)
+
+			_, err := c.core.Vote(ctx, &rarimocore.QueryGetVoteRequest{
+				Operation: op.Index,
+				Validator: c.router.Sender(),
+			})
+			if err == nil {
+				log.Debug("Operation already voted")
+				continue
+			}
+
+			if err := c.router.Process(ctx, op); err != nil {
+				log.WithError(err).Error("failed to process operation")
+			}
+		}
+
+		nextKey = resp.Pagination.NextKey
+		if nextKey == nil {
+			c.log.Info("Finished catchup unvoted operations")
+			return nil
+		}
+	}
+}
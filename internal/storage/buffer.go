@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const bufferPrefix = "buffer"
+
+// Buffer keeps deposit events scanned while broadcasting is paused, ordered by (block, log index).
+type Buffer struct {
+	db *leveldb.DB
+}
+
+func (b *Buffer) Put(network, listener string, log types.Log) error {
+	raw, err := json.Marshal(log)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal buffered log")
+	}
+
+	if err := b.db.Put(bufferKey(network, listener, log), raw, syncWrite); err != nil {
+		return errors.Wrap(err, "failed to put buffered log")
+	}
+
+	return nil
+}
+
+// List returns buffered logs of the listener in (block, log index) order.
+func (b *Buffer) List(network, listener string) ([]types.Log, error) {
+	iter := b.db.NewIterator(util.BytesPrefix(bufferListenerPrefix(network, listener)), nil)
+	defer iter.Release()
+
+	var result []types.Log
+
+	for iter.Next() {
+		var log types.Log
+		if err := json.Unmarshal(iter.Value(), &log); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal buffered log")
+		}
+
+		result = append(result, log)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate buffered logs")
+	}
+
+	return result, nil
+}
+
+// Count returns the amount of buffered logs of the listener.
+func (b *Buffer) Count(network, listener string) (uint64, error) {
+	iter := b.db.NewIterator(util.BytesPrefix(bufferListenerPrefix(network, listener)), nil)
+	defer iter.Release()
+
+	var count uint64
+	for iter.Next() {
+		count++
+	}
+
+	if err := iter.Error(); err != nil {
+		return 0, errors.Wrap(err, "failed to iterate buffered logs")
+	}
+
+	return count, nil
+}
+
+func (b *Buffer) Delete(network, listener string, log types.Log) error {
+	if err := b.db.Delete(bufferKey(network, listener, log), syncWrite); err != nil {
+		return errors.Wrap(err, "failed to delete buffered log")
+	}
+
+	return nil
+}
+
+func bufferListenerPrefix(network, listener string) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s/", bufferPrefix, network, listener))
+}
+
+func bufferKey(network, listener string, log types.Log) []byte {
+	key := blockKey(bufferListenerPrefix(network, listener), log.BlockNumber)
+	return blockKey(key, uint64(log.Index))
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const checkpointsPrefix = "checkpoint"
+
+// Checkpoints keeps the last fully processed block for every listener.
+type Checkpoints struct {
+	db *leveldb.DB
+}
+
+// Get returns the last fully processed block of the listener. The second value is false if
+// the listener has never saved its progress.
+func (c *Checkpoints) Get(network, listener string) (uint64, bool, error) {
+	raw, err := c.db.Get(checkpointKey(network, listener), nil)
+	if err == leveldb.ErrNotFound {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to get checkpoint")
+	}
+
+	if len(raw) != 8 {
+		return 0, false, errors.New("malformed checkpoint value")
+	}
+
+	return binary.BigEndian.Uint64(raw), true, nil
+}
+
+func (c *Checkpoints) Set(network, listener string, block uint64) error {
+	raw := make([]byte, 8)
+	binary.BigEndian.PutUint64(raw, block)
+
+	if err := c.db.Put(checkpointKey(network, listener), raw, syncWrite); err != nil {
+		return errors.Wrap(err, "failed to put checkpoint")
+	}
+
+	return nil
+}
+
+func (c *Checkpoints) Delete(network, listener string) error {
+	if err := c.db.Delete(checkpointKey(network, listener), syncWrite); err != nil {
+		return errors.Wrap(err, "failed to delete checkpoint")
+	}
+
+	return nil
+}
+
+func checkpointKey(network, listener string) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s", checkpointsPrefix, network, listener))
+}
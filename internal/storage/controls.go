@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const controlsPrefix = "control"
+
+// ListenerControl is the operator set state of the listener.
+type ListenerControl struct {
+	Network  string `json:"network"`
+	Listener string `json:"listener"`
+	// Paused stops the listener from scanning, its checkpoint stays where it is
+	Paused bool `json:"paused"`
+	// BroadcastPaused makes the listener keep scanning, but buffer deposits instead of submitting them
+	BroadcastPaused bool `json:"broadcast_paused"`
+	// RewindTo is the block the listener is to continue from, it is cleared once the listener applies it
+	RewindTo  *uint64   `json:"rewind_to,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Controls keeps listeners state set through the admin api. Both the api and listeners change it, so
+// changes are serialized.
+type Controls struct {
+	db *leveldb.DB
+	mu *sync.Mutex
+}
+
+// Update applies the change to the listener control and saves it.
+func (c *Controls) Update(network, listener string, change func(control *ListenerControl)) (ListenerControl, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	control, err := c.Get(network, listener)
+	if err != nil {
+		return control, err
+	}
+
+	change(&control)
+	control.UpdatedAt = time.Now().UTC()
+
+	raw, err := json.Marshal(control)
+	if err != nil {
+		return control, errors.Wrap(err, "failed to marshal listener control")
+	}
+
+	if err := c.db.Put(controlKey(network, listener), raw, syncWrite); err != nil {
+		return control, errors.Wrap(err, "failed to put listener control")
+	}
+
+	return control, nil
+}
+
+// Get returns the control of the listener, the zero one if it has never been set.
+func (c *Controls) Get(network, listener string) (ListenerControl, error) {
+	control := ListenerControl{Network: network, Listener: listener}
+
+	raw, err := c.db.Get(controlKey(network, listener), nil)
+	if err == leveldb.ErrNotFound {
+		return control, nil
+	}
+
+	if err != nil {
+		return control, errors.Wrap(err, "failed to get listener control")
+	}
+
+	if err := json.Unmarshal(raw, &control); err != nil {
+		return control, errors.Wrap(err, "failed to unmarshal listener control")
+	}
+
+	return control, nil
+}
+
+// List returns controls set for listeners of the network, all networks are listed if it is empty.
+func (c *Controls) List(network string) ([]ListenerControl, error) {
+	prefix := controlsPrefix + "/"
+	if network != "" {
+		prefix += network + "/"
+	}
+
+	iter := c.db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+	defer iter.Release()
+
+	var result []ListenerControl
+
+	for iter.Next() {
+		var control ListenerControl
+		if err := json.Unmarshal(iter.Value(), &control); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal listener control")
+		}
+
+		result = append(result, control)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate listener controls")
+	}
+
+	return result, nil
+}
+
+func controlKey(network, listener string) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s", controlsPrefix, network, listener))
+}
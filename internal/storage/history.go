@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const historyPrefix = "history"
+
+// BlockHash is the hash a block had when the listener processed it.
+type BlockHash struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// SubmittedTransfer is a deposit event the listener has broadcast a transfer message for.
+type SubmittedTransfer struct {
+	Block     uint64      `json:"block"`
+	BlockHash common.Hash `json:"block_hash"`
+	TxHash    common.Hash `json:"tx_hash"`
+	LogIndex  uint        `json:"log_index"`
+}
+
+// History keeps recently processed blocks and submitted transfers of every listener to detect reorgs
+// and find transfers orphaned by them.
+type History struct {
+	db *leveldb.DB
+}
+
+func (h *History) PutBlock(network, listener string, block BlockHash) error {
+	if err := h.db.Put(historyBlockKey(network, listener, block.Number), block.Hash.Bytes(), syncWrite); err != nil {
+		return errors.Wrap(err, "failed to put block hash")
+	}
+
+	return nil
+}
+
+// Blocks returns remembered blocks sorted by number.
+func (h *History) Blocks(network, listener string) ([]BlockHash, error) {
+	iter := h.db.NewIterator(util.BytesPrefix(historyBlocksPrefix(network, listener)), nil)
+	defer iter.Release()
+
+	var result []BlockHash
+
+	for iter.Next() {
+		key := iter.Key()
+		result = append(result, BlockHash{
+			Number: binary.BigEndian.Uint64(key[len(key)-8:]),
+			Hash:   common.BytesToHash(iter.Value()),
+		})
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate block hashes")
+	}
+
+	return result, nil
+}
+
+func (h *History) PutTransfer(network, listener string, transfer SubmittedTransfer) error {
+	raw, err := json.Marshal(transfer)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal submitted transfer")
+	}
+
+	if err := h.db.Put(historyTransferKey(network, listener, transfer), raw, syncWrite); err != nil {
+		return errors.Wrap(err, "failed to put submitted transfer")
+	}
+
+	return nil
+}
+
+// Transfers returns remembered transfers submitted from blocks after the given one.
+func (h *History) Transfers(network, listener string, after uint64) ([]SubmittedTransfer, error) {
+	prefix := historyTransfersPrefix(network, listener)
+
+	iter := h.db.NewIterator(&util.Range{
+		Start: blockKey(prefix, after+1),
+		Limit: util.BytesPrefix(prefix).Limit,
+	}, nil)
+	defer iter.Release()
+
+	var result []SubmittedTransfer
+
+	for iter.Next() {
+		var transfer SubmittedTransfer
+		if err := json.Unmarshal(iter.Value(), &transfer); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal submitted transfer")
+		}
+
+		result = append(result, transfer)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate submitted transfers")
+	}
+
+	return result, nil
+}
+
+// Rewind forgets blocks and transfers after the given block.
+func (h *History) Rewind(network, listener string, to uint64) error {
+	batch := new(leveldb.Batch)
+
+	for _, prefix := range [][]byte{historyBlocksPrefix(network, listener), historyTransfersPrefix(network, listener)} {
+		h.collect(batch, &util.Range{
+			Start: blockKey(prefix, to+1),
+			Limit: util.BytesPrefix(prefix).Limit,
+		})
+	}
+
+	if err := h.db.Write(batch, syncWrite); err != nil {
+		return errors.Wrap(err, "failed to rewind history")
+	}
+
+	return nil
+}
+
+// Prune keeps only the given amount of the latest blocks and transfers that are not older than them.
+func (h *History) Prune(network, listener string, keep int) error {
+	blocks, err := h.Blocks(network, listener)
+	if err != nil {
+		return errors.Wrap(err, "failed to get blocks")
+	}
+
+	if len(blocks) <= keep {
+		return nil
+	}
+
+	oldest := blocks[len(blocks)-keep].Number
+	batch := new(leveldb.Batch)
+
+	for _, prefix := range [][]byte{historyBlocksPrefix(network, listener), historyTransfersPrefix(network, listener)} {
+		h.collect(batch, &util.Range{
+			Start: prefix,
+			Limit: blockKey(prefix, oldest),
+		})
+	}
+
+	if err := h.db.Write(batch, syncWrite); err != nil {
+		return errors.Wrap(err, "failed to prune history")
+	}
+
+	return nil
+}
+
+func (h *History) collect(batch *leveldb.Batch, keys *util.Range) {
+	iter := h.db.NewIterator(keys, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+}
+
+func historyBlocksPrefix(network, listener string) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s/block/", historyPrefix, network, listener))
+}
+
+func historyTransfersPrefix(network, listener string) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s/transfer/", historyPrefix, network, listener))
+}
+
+func historyBlockKey(network, listener string, block uint64) []byte {
+	return blockKey(historyBlocksPrefix(network, listener), block)
+}
+
+func historyTransferKey(network, listener string, transfer SubmittedTransfer) []byte {
+	key := blockKey(historyTransfersPrefix(network, listener), transfer.Block)
+	return append(key, []byte(fmt.Sprintf("/%s/%d", transfer.TxHash.String(), transfer.LogIndex))...)
+}
+
+// blockKey appends the big endian block number to the prefix, so keys are sorted by block.
+func blockKey(prefix []byte, block uint64) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], block)
+	return key
+}
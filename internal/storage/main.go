@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"sync"
+	"syscall"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Storage is the local embedded key-value database keeping the service state between restarts.
+type Storage struct {
+	db *leveldb.DB
+
+	controlsMu sync.Mutex
+}
+
+// ErrLocked means the storage is open by another process, leveldb can not be shared between processes.
+var ErrLocked = errors.New("storage is locked by another process")
+
+func New(path string) (*Storage, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err == syscall.EWOULDBLOCK {
+		return nil, errors.From(ErrLocked, logan.F{
+			"path": path,
+		})
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open leveldb", logan.F{
+			"path": path,
+		})
+	}
+
+	return &Storage{db: db}, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+func (s *Storage) Checkpoints() *Checkpoints {
+	return &Checkpoints{db: s.db}
+}
+
+func (s *Storage) Quarantine() *Quarantine {
+	return &Quarantine{db: s.db}
+}
+
+func (s *Storage) Rejections() *Rejections {
+	return &Rejections{db: s.db}
+}
+
+func (s *Storage) History() *History {
+	return &History{db: s.db}
+}
+
+func (s *Storage) Controls() *Controls {
+	return &Controls{db: s.db, mu: &s.controlsMu}
+}
+
+func (s *Storage) Buffer() *Buffer {
+	return &Buffer{db: s.db}
+}
+
+func (s *Storage) Proofs() *Proofs {
+	return &Proofs{db: s.db}
+}
+
+// syncWrite makes every write durable before returning, so the state survives a crash right after it.
+var syncWrite = &opt.WriteOptions{Sync: true}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/syndtr/goleveldb/leveldb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const proofsPrefix = "proofs"
+
+// ReceiptProof proves the transaction and its receipt are included in the canonical block. Both tries
+// are keyed by the rlp encoded transaction index, their nodes are listed in no particular order and
+// are looked up by keccak hash. Log indexes are global in the block, so the receipt logs start from
+// FirstLogIndex, the number of logs of the earlier receipts. ReceiptProof includes the paths to the
+// earlier receipts as well, so the index is proven by them.
+type ReceiptProof struct {
+	Network       string          `json:"network"`
+	TxHash        common.Hash     `json:"tx_hash"`
+	BlockNumber   uint64          `json:"block_number"`
+	BlockHash     common.Hash     `json:"block_hash"`
+	Header        hexutil.Bytes   `json:"header"`
+	TxIndex       uint            `json:"tx_index"`
+	FirstLogIndex uint            `json:"first_log_index"`
+	TxProof       []hexutil.Bytes `json:"tx_proof"`
+	ReceiptProof  []hexutil.Bytes `json:"receipt_proof"`
+	ProvenAt      time.Time       `json:"proven_at"`
+}
+
+// Proofs keeps receipt proofs of deposit transactions, so votes can be audited later.
+type Proofs struct {
+	db *leveldb.DB
+}
+
+func (p *Proofs) Put(proof ReceiptProof) error {
+	raw, err := json.Marshal(proof)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal receipt proof")
+	}
+
+	if err := p.db.Put(proofKey(proof.Network, proof.TxHash), raw, syncWrite); err != nil {
+		return errors.Wrap(err, "failed to put receipt proof")
+	}
+
+	return nil
+}
+
+// Get returns nil if the transaction has not been proven.
+func (p *Proofs) Get(network string, txHash common.Hash) (*ReceiptProof, error) {
+	raw, err := p.db.Get(proofKey(network, txHash), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get receipt proof")
+	}
+
+	var proof ReceiptProof
+	if err := json.Unmarshal(raw, &proof); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal receipt proof")
+	}
+
+	return &proof, nil
+}
+
+func proofKey(network string, txHash common.Hash) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s", proofsPrefix, network, txHash.Hex()))
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const quarantinePrefix = "quarantine"
+
+// QuarantinedEvent is a deposit event that can not be turned into a transfer message and waits for
+// an operator to retry or discard it.
+type QuarantinedEvent struct {
+	Network       string    `json:"network"`
+	Listener      string    `json:"listener"`
+	Error         string    `json:"error"`
+	Attempts      uint64    `json:"attempts"`
+	Log           types.Log `json:"log"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
+
+// Quarantine keeps poison deposit events set aside by listeners.
+type Quarantine struct {
+	db *leveldb.DB
+}
+
+func (q *Quarantine) Put(event QuarantinedEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal quarantined event")
+	}
+
+	key := quarantineKey(event.Network, event.Log.TxHash.String(), event.Log.Index)
+	if err := q.db.Put(key, raw, syncWrite); err != nil {
+		return errors.Wrap(err, "failed to put quarantined event")
+	}
+
+	return nil
+}
+
+// Get returns nil if there is no such event in the quarantine.
+func (q *Quarantine) Get(network, txHash string, logIndex uint) (*QuarantinedEvent, error) {
+	raw, err := q.db.Get(quarantineKey(network, txHash, logIndex), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get quarantined event")
+	}
+
+	var event QuarantinedEvent
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal quarantined event")
+	}
+
+	return &event, nil
+}
+
+// List returns quarantined events of the network, all networks are listed if it is empty.
+func (q *Quarantine) List(network string) ([]QuarantinedEvent, error) {
+	prefix := quarantinePrefix + "/"
+	if network != "" {
+		prefix += network + "/"
+	}
+
+	iter := q.db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+	defer iter.Release()
+
+	var result []QuarantinedEvent
+
+	for iter.Next() {
+		var event QuarantinedEvent
+		if err := json.Unmarshal(iter.Value(), &event); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal quarantined event")
+		}
+
+		result = append(result, event)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate quarantined events")
+	}
+
+	return result, nil
+}
+
+func (q *Quarantine) Delete(network, txHash string, logIndex uint) error {
+	if err := q.db.Delete(quarantineKey(network, txHash, logIndex), syncWrite); err != nil {
+		return errors.Wrap(err, "failed to delete quarantined event")
+	}
+
+	return nil
+}
+
+func quarantineKey(network, txHash string, logIndex uint) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s/%d", quarantinePrefix, network, txHash, logIndex))
+}
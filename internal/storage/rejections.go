@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const rejectionsPrefix = "rejected"
+
+// RejectedEvent is a deposit event the deposit policy does not allow to submit.
+type RejectedEvent struct {
+	Network    string    `json:"network"`
+	Listener   string    `json:"listener"`
+	Rule       string    `json:"rule"`
+	Details    string    `json:"details"`
+	Log        types.Log `json:"log"`
+	RejectedAt time.Time `json:"rejected_at"`
+}
+
+// Rejections keeps deposit events rejected by the policy for operator review.
+type Rejections struct {
+	db *leveldb.DB
+}
+
+func (r *Rejections) Put(event RejectedEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal rejected event")
+	}
+
+	key := rejectionKey(event.Network, event.Log.TxHash.String(), event.Log.Index)
+	if err := r.db.Put(key, raw, syncWrite); err != nil {
+		return errors.Wrap(err, "failed to put rejected event")
+	}
+
+	return nil
+}
+
+// List returns rejected events of the network, all networks are listed if it is empty.
+func (r *Rejections) List(network string) ([]RejectedEvent, error) {
+	prefix := rejectionsPrefix + "/"
+	if network != "" {
+		prefix += network + "/"
+	}
+
+	iter := r.db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+	defer iter.Release()
+
+	var result []RejectedEvent
+
+	for iter.Next() {
+		var event RejectedEvent
+		if err := json.Unmarshal(iter.Value(), &event); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal rejected event")
+		}
+
+		result = append(result, event)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate rejected events")
+	}
+
+	return result, nil
+}
+
+func rejectionKey(network, txHash string, logIndex uint) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s/%d", rejectionsPrefix, network, txHash, logIndex))
+}
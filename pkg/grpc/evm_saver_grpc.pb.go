@@ -0,0 +1,553 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: evm_saver.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	EvmSaver_ListQuarantined_FullMethodName    = "/evmsaver.EvmSaver/ListQuarantined"
+	EvmSaver_RetryQuarantined_FullMethodName   = "/evmsaver.EvmSaver/RetryQuarantined"
+	EvmSaver_DiscardQuarantined_FullMethodName = "/evmsaver.EvmSaver/DiscardQuarantined"
+	EvmSaver_ListRejected_FullMethodName       = "/evmsaver.EvmSaver/ListRejected"
+	EvmSaver_ListListeners_FullMethodName      = "/evmsaver.EvmSaver/ListListeners"
+	EvmSaver_SubmitDeposit_FullMethodName      = "/evmsaver.EvmSaver/SubmitDeposit"
+	EvmSaver_GetReceiptProof_FullMethodName    = "/evmsaver.EvmSaver/GetReceiptProof"
+	EvmSaver_ListControls_FullMethodName       = "/evmsaver.EvmSaver/ListControls"
+	EvmSaver_PauseListener_FullMethodName      = "/evmsaver.EvmSaver/PauseListener"
+	EvmSaver_ResumeListener_FullMethodName     = "/evmsaver.EvmSaver/ResumeListener"
+	EvmSaver_PauseBroadcast_FullMethodName     = "/evmsaver.EvmSaver/PauseBroadcast"
+	EvmSaver_ResumeBroadcast_FullMethodName    = "/evmsaver.EvmSaver/ResumeBroadcast"
+	EvmSaver_RewindListener_FullMethodName     = "/evmsaver.EvmSaver/RewindListener"
+)
+
+// EvmSaverClient is the client API for EvmSaver service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EvmSaverClient interface {
+	ListQuarantined(ctx context.Context, in *ListQuarantinedRequest, opts ...grpc.CallOption) (*ListQuarantinedResponse, error)
+	RetryQuarantined(ctx context.Context, in *QuarantinedEventRequest, opts ...grpc.CallOption) (*RetryQuarantinedResponse, error)
+	DiscardQuarantined(ctx context.Context, in *QuarantinedEventRequest, opts ...grpc.CallOption) (*DiscardQuarantinedResponse, error)
+	ListRejected(ctx context.Context, in *ListRejectedRequest, opts ...grpc.CallOption) (*ListRejectedResponse, error)
+	ListListeners(ctx context.Context, in *ListListenersRequest, opts ...grpc.CallOption) (*ListListenersResponse, error)
+	SubmitDeposit(ctx context.Context, in *SubmitDepositRequest, opts ...grpc.CallOption) (*SubmitDepositResponse, error)
+	GetReceiptProof(ctx context.Context, in *GetReceiptProofRequest, opts ...grpc.CallOption) (*ReceiptProof, error)
+	ListControls(ctx context.Context, in *ListControlsRequest, opts ...grpc.CallOption) (*ListControlsResponse, error)
+	PauseListener(ctx context.Context, in *ListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error)
+	ResumeListener(ctx context.Context, in *ListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error)
+	PauseBroadcast(ctx context.Context, in *ListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error)
+	ResumeBroadcast(ctx context.Context, in *ListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error)
+	RewindListener(ctx context.Context, in *RewindListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error)
+}
+
+type evmSaverClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEvmSaverClient(cc grpc.ClientConnInterface) EvmSaverClient {
+	return &evmSaverClient{cc}
+}
+
+func (c *evmSaverClient) ListQuarantined(ctx context.Context, in *ListQuarantinedRequest, opts ...grpc.CallOption) (*ListQuarantinedResponse, error) {
+	out := new(ListQuarantinedResponse)
+	err := c.cc.Invoke(ctx, EvmSaver_ListQuarantined_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) RetryQuarantined(ctx context.Context, in *QuarantinedEventRequest, opts ...grpc.CallOption) (*RetryQuarantinedResponse, error) {
+	out := new(RetryQuarantinedResponse)
+	err := c.cc.Invoke(ctx, EvmSaver_RetryQuarantined_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) DiscardQuarantined(ctx context.Context, in *QuarantinedEventRequest, opts ...grpc.CallOption) (*DiscardQuarantinedResponse, error) {
+	out := new(DiscardQuarantinedResponse)
+	err := c.cc.Invoke(ctx, EvmSaver_DiscardQuarantined_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) ListRejected(ctx context.Context, in *ListRejectedRequest, opts ...grpc.CallOption) (*ListRejectedResponse, error) {
+	out := new(ListRejectedResponse)
+	err := c.cc.Invoke(ctx, EvmSaver_ListRejected_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) ListListeners(ctx context.Context, in *ListListenersRequest, opts ...grpc.CallOption) (*ListListenersResponse, error) {
+	out := new(ListListenersResponse)
+	err := c.cc.Invoke(ctx, EvmSaver_ListListeners_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) SubmitDeposit(ctx context.Context, in *SubmitDepositRequest, opts ...grpc.CallOption) (*SubmitDepositResponse, error) {
+	out := new(SubmitDepositResponse)
+	err := c.cc.Invoke(ctx, EvmSaver_SubmitDeposit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) GetReceiptProof(ctx context.Context, in *GetReceiptProofRequest, opts ...grpc.CallOption) (*ReceiptProof, error) {
+	out := new(ReceiptProof)
+	err := c.cc.Invoke(ctx, EvmSaver_GetReceiptProof_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) ListControls(ctx context.Context, in *ListControlsRequest, opts ...grpc.CallOption) (*ListControlsResponse, error) {
+	out := new(ListControlsResponse)
+	err := c.cc.Invoke(ctx, EvmSaver_ListControls_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) PauseListener(ctx context.Context, in *ListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error) {
+	out := new(ListenerControl)
+	err := c.cc.Invoke(ctx, EvmSaver_PauseListener_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) ResumeListener(ctx context.Context, in *ListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error) {
+	out := new(ListenerControl)
+	err := c.cc.Invoke(ctx, EvmSaver_ResumeListener_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) PauseBroadcast(ctx context.Context, in *ListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error) {
+	out := new(ListenerControl)
+	err := c.cc.Invoke(ctx, EvmSaver_PauseBroadcast_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) ResumeBroadcast(ctx context.Context, in *ListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error) {
+	out := new(ListenerControl)
+	err := c.cc.Invoke(ctx, EvmSaver_ResumeBroadcast_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evmSaverClient) RewindListener(ctx context.Context, in *RewindListenerRequest, opts ...grpc.CallOption) (*ListenerControl, error) {
+	out := new(ListenerControl)
+	err := c.cc.Invoke(ctx, EvmSaver_RewindListener_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EvmSaverServer is the server API for EvmSaver service.
+// All implementations must embed UnimplementedEvmSaverServer
+// for forward compatibility
+type EvmSaverServer interface {
+	ListQuarantined(context.Context, *ListQuarantinedRequest) (*ListQuarantinedResponse, error)
+	RetryQuarantined(context.Context, *QuarantinedEventRequest) (*RetryQuarantinedResponse, error)
+	DiscardQuarantined(context.Context, *QuarantinedEventRequest) (*DiscardQuarantinedResponse, error)
+	ListRejected(context.Context, *ListRejectedRequest) (*ListRejectedResponse, error)
+	ListListeners(context.Context, *ListListenersRequest) (*ListListenersResponse, error)
+	SubmitDeposit(context.Context, *SubmitDepositRequest) (*SubmitDepositResponse, error)
+	GetReceiptProof(context.Context, *GetReceiptProofRequest) (*ReceiptProof, error)
+	ListControls(context.Context, *ListControlsRequest) (*ListControlsResponse, error)
+	PauseListener(context.Context, *ListenerRequest) (*ListenerControl, error)
+	ResumeListener(context.Context, *ListenerRequest) (*ListenerControl, error)
+	PauseBroadcast(context.Context, *ListenerRequest) (*ListenerControl, error)
+	ResumeBroadcast(context.Context, *ListenerRequest) (*ListenerControl, error)
+	RewindListener(context.Context, *RewindListenerRequest) (*ListenerControl, error)
+	mustEmbedUnimplementedEvmSaverServer()
+}
+
+// UnimplementedEvmSaverServer must be embedded to have forward compatible implementations.
+type UnimplementedEvmSaverServer struct {
+}
+
+func (UnimplementedEvmSaverServer) ListQuarantined(context.Context, *ListQuarantinedRequest) (*ListQuarantinedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuarantined not implemented")
+}
+func (UnimplementedEvmSaverServer) RetryQuarantined(context.Context, *QuarantinedEventRequest) (*RetryQuarantinedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryQuarantined not implemented")
+}
+func (UnimplementedEvmSaverServer) DiscardQuarantined(context.Context, *QuarantinedEventRequest) (*DiscardQuarantinedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscardQuarantined not implemented")
+}
+func (UnimplementedEvmSaverServer) ListRejected(context.Context, *ListRejectedRequest) (*ListRejectedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRejected not implemented")
+}
+func (UnimplementedEvmSaverServer) ListListeners(context.Context, *ListListenersRequest) (*ListListenersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListListeners not implemented")
+}
+func (UnimplementedEvmSaverServer) SubmitDeposit(context.Context, *SubmitDepositRequest) (*SubmitDepositResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitDeposit not implemented")
+}
+func (UnimplementedEvmSaverServer) GetReceiptProof(context.Context, *GetReceiptProofRequest) (*ReceiptProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReceiptProof not implemented")
+}
+func (UnimplementedEvmSaverServer) ListControls(context.Context, *ListControlsRequest) (*ListControlsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListControls not implemented")
+}
+func (UnimplementedEvmSaverServer) PauseListener(context.Context, *ListenerRequest) (*ListenerControl, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseListener not implemented")
+}
+func (UnimplementedEvmSaverServer) ResumeListener(context.Context, *ListenerRequest) (*ListenerControl, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeListener not implemented")
+}
+func (UnimplementedEvmSaverServer) PauseBroadcast(context.Context, *ListenerRequest) (*ListenerControl, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseBroadcast not implemented")
+}
+func (UnimplementedEvmSaverServer) ResumeBroadcast(context.Context, *ListenerRequest) (*ListenerControl, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeBroadcast not implemented")
+}
+func (UnimplementedEvmSaverServer) RewindListener(context.Context, *RewindListenerRequest) (*ListenerControl, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RewindListener not implemented")
+}
+func (UnimplementedEvmSaverServer) mustEmbedUnimplementedEvmSaverServer() {}
+
+// UnsafeEvmSaverServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EvmSaverServer will
+// result in compilation errors.
+type UnsafeEvmSaverServer interface {
+	mustEmbedUnimplementedEvmSaverServer()
+}
+
+func RegisterEvmSaverServer(s grpc.ServiceRegistrar, srv EvmSaverServer) {
+	s.RegisterService(&EvmSaver_ServiceDesc, srv)
+}
+
+func _EvmSaver_ListQuarantined_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQuarantinedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).ListQuarantined(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_ListQuarantined_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).ListQuarantined(ctx, req.(*ListQuarantinedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_RetryQuarantined_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuarantinedEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).RetryQuarantined(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_RetryQuarantined_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).RetryQuarantined(ctx, req.(*QuarantinedEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_DiscardQuarantined_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuarantinedEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).DiscardQuarantined(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_DiscardQuarantined_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).DiscardQuarantined(ctx, req.(*QuarantinedEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_ListRejected_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRejectedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).ListRejected(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_ListRejected_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).ListRejected(ctx, req.(*ListRejectedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_ListListeners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListListenersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).ListListeners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_ListListeners_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).ListListeners(ctx, req.(*ListListenersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_SubmitDeposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitDepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).SubmitDeposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_SubmitDeposit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).SubmitDeposit(ctx, req.(*SubmitDepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_GetReceiptProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReceiptProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).GetReceiptProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_GetReceiptProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).GetReceiptProof(ctx, req.(*GetReceiptProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_ListControls_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListControlsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).ListControls(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_ListControls_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).ListControls(ctx, req.(*ListControlsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_PauseListener_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListenerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).PauseListener(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_PauseListener_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).PauseListener(ctx, req.(*ListenerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_ResumeListener_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListenerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).ResumeListener(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_ResumeListener_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).ResumeListener(ctx, req.(*ListenerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_PauseBroadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListenerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).PauseBroadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_PauseBroadcast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).PauseBroadcast(ctx, req.(*ListenerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_ResumeBroadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListenerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).ResumeBroadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_ResumeBroadcast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).ResumeBroadcast(ctx, req.(*ListenerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvmSaver_RewindListener_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RewindListenerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvmSaverServer).RewindListener(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvmSaver_RewindListener_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvmSaverServer).RewindListener(ctx, req.(*RewindListenerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EvmSaver_ServiceDesc is the grpc.ServiceDesc for EvmSaver service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EvmSaver_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "evmsaver.EvmSaver",
+	HandlerType: (*EvmSaverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListQuarantined",
+			Handler:    _EvmSaver_ListQuarantined_Handler,
+		},
+		{
+			MethodName: "RetryQuarantined",
+			Handler:    _EvmSaver_RetryQuarantined_Handler,
+		},
+		{
+			MethodName: "DiscardQuarantined",
+			Handler:    _EvmSaver_DiscardQuarantined_Handler,
+		},
+		{
+			MethodName: "ListRejected",
+			Handler:    _EvmSaver_ListRejected_Handler,
+		},
+		{
+			MethodName: "ListListeners",
+			Handler:    _EvmSaver_ListListeners_Handler,
+		},
+		{
+			MethodName: "SubmitDeposit",
+			Handler:    _EvmSaver_SubmitDeposit_Handler,
+		},
+		{
+			MethodName: "GetReceiptProof",
+			Handler:    _EvmSaver_GetReceiptProof_Handler,
+		},
+		{
+			MethodName: "ListControls",
+			Handler:    _EvmSaver_ListControls_Handler,
+		},
+		{
+			MethodName: "PauseListener",
+			Handler:    _EvmSaver_PauseListener_Handler,
+		},
+		{
+			MethodName: "ResumeListener",
+			Handler:    _EvmSaver_ResumeListener_Handler,
+		},
+		{
+			MethodName: "PauseBroadcast",
+			Handler:    _EvmSaver_PauseBroadcast_Handler,
+		},
+		{
+			MethodName: "ResumeBroadcast",
+			Handler:    _EvmSaver_ResumeBroadcast_Handler,
+		},
+		{
+			MethodName: "RewindListener",
+			Handler:    _EvmSaver_RewindListener_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "evm_saver.proto",
+}